@@ -0,0 +1,67 @@
+package cplatform
+
+import (
+	"context"
+	"io"
+
+	confluent "github.com/OneMount/gonfluent"
+)
+
+// The resources only use the backends below through the meta, *confluent.Client implements all of them.
+// The topics have their own backend, see topic_api.go.
+var (
+	_ restAPI          = (*confluent.Client)(nil)
+	_ roleBindingAdmin = (*confluent.Client)(nil)
+	_ clusterRegistry  = (*confluent.Client)(nil)
+	_ topicAPI         = (*confluent.Client)(nil)
+)
+
+// restAPI sends the requests to the REST API and MDS which have no method in gonfluent:
+// the cluster links, the lookups of the role bindings and the cluster registry
+type restAPI interface {
+	DoRequest(method string, uri string, reqBody io.Reader) ([]byte, error)
+}
+
+// roleBindingAdmin manages the role bindings of MDS
+type roleBindingAdmin interface {
+	BindPrincipalToRole(principal, roleName string, cDetails confluent.ClusterDetails) error
+	DeleteRoleBinding(principal, roleName string, cDetails confluent.ClusterDetails) error
+	LookupRoleBinding(principal, roleName string, cDetails confluent.ClusterDetails) ([]confluent.ResourcePattern, error)
+	IncreaseRoleBinding(principal, roleName string, uRoleBinding confluent.RoleBinding) error
+	DecreaseRoleBinding(principal, roleName string, uRoleBinding confluent.RoleBinding) error
+}
+
+// clusterRegistry lists the Kafka clusters of the REST API
+type clusterRegistry interface {
+	ListKafkaCluster() ([]confluent.KafkaCluster, error)
+	GetKafkaCluster(clusterId string) (*confluent.KafkaCluster, error)
+}
+
+// clientQuotaAdmin manages the client quotas of Kafka, which gonfluent does not provide, see kafka_quotas.go
+type clientQuotaAdmin interface {
+	describeClientQuotas(ctx context.Context, entity quotaEntity) (map[string]float64, error)
+	alterClientQuotas(ctx context.Context, entity quotaEntity, set map[string]float64, remove []string) error
+}
+
+// useGonfluent sets every backend of the meta to the gonfluent client, except the quotas which use franz-go
+func (c *Client) useGonfluent(client *confluent.Client, topicApi string) {
+	c.rest = client
+	c.roleBindings = client
+	c.clusters = client
+	c.topics = newTopicAPI(c, client, topicApi)
+	c.clientQuotas = kgoQuotaAdmin{c}
+}
+
+// hasResourcePattern tells if the role binding of the principal at the given scope has the resource pattern
+func hasResourcePattern(c roleBindingAdmin, principal, role string, cDetails confluent.ClusterDetails, pattern confluent.ResourcePattern) (bool, error) {
+	patterns, err := c.LookupRoleBinding(principal, role, cDetails)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range patterns {
+		if v.ResourceType == pattern.ResourceType && v.Name == pattern.Name && v.PatternType == pattern.PatternType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
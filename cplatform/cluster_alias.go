@@ -0,0 +1,205 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// subClusterIdAttributes are the attributes of the IDs of the clusters other than Kafka, by cluster_type
+var subClusterIdAttributes = map[string]string{
+	"SchemaRegistry": "schema_registry_cluster_id",
+	"Connect":        "connect_cluster_id",
+	"KSQL":           "ksql_cluster_id",
+}
+
+// clustersSchema is the clusters block of the provider, each alias names the IDs of the clusters of a Confluent Platform
+func clustersSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Aliases of the clusters, the cluster IDs of the resources can be an alias instead of an ID",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"alias": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"kafka_cluster_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The ID of the Kafka cluster, the cluster_id of the resources using the alias",
+				},
+				"schema_registry_cluster_id": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"connect_cluster_id": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"ksql_cluster_id": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// clusterAliasesOf reads the clusters block of the provider: the IDs of each alias, by attribute of the resources
+func clusterAliasesOf(blocks []interface{}) (map[string]map[string]string, error) {
+	aliases := make(map[string]map[string]string, len(blocks))
+	for _, b := range blocks {
+		m := b.(map[string]interface{})
+		alias := m["alias"].(string)
+		if _, ok := aliases[alias]; ok {
+			return nil, fmt.Errorf("the cluster alias %q is declared twice", alias)
+		}
+
+		ids := map[string]string{"cluster_id": m["kafka_cluster_id"].(string)}
+		for _, k := range subClusterIdAttributes {
+			ids[k] = m[k].(string)
+		}
+		aliases[alias] = ids
+	}
+	return aliases, nil
+}
+
+// resolveClusterId returns the ID of the cluster of an attribute: the ID of the alias when value is an alias,
+// default_kafka_cluster_id when cluster_id is empty, else value itself
+func (c *Client) resolveClusterId(attribute, value string) (string, error) {
+	if value == "" && attribute == "cluster_id" {
+		value = c.defaultKafkaClusterId
+	}
+	ids, ok := c.clusterAliases[value]
+	if !ok {
+		return value, nil
+	}
+	if ids[attribute] == "" {
+		name := attribute
+		if attribute == "cluster_id" {
+			name = "kafka_cluster_id"
+		}
+		return "", fmt.Errorf("the cluster alias %q has no %s, it cannot be the %s", value, name, attribute)
+	}
+	return ids[attribute], nil
+}
+
+// clusterIdOf returns the ID of the cluster of an attribute of a resource being created. The plan has the real IDs, an
+// alias is still resolved in case the plan was made before the alias was known. An empty cluster_id is
+// default_kafka_cluster_id.
+func (c *Client) clusterIdOf(d *schema.ResourceData, attribute string) (string, diag.Diagnostics) {
+	id, err := c.resolveClusterId(attribute, d.Get(attribute).(string))
+	if err != nil {
+		return "", errorDiags("Cannot resolve the cluster alias of "+attribute, err, attribute)
+	}
+	if id == "" && attribute == "cluster_id" {
+		return "", diag.Diagnostics{newDiagnostic(diag.Error, "Missing cluster_id", "cluster_id is required when the provider has no default_kafka_cluster_id.", attribute)}
+	}
+	return id, nil
+}
+
+// resolveClusterIds returns the real IDs of cluster_id and of the attributes of the other clusters the resource needs:
+// an empty cluster_id is default_kafka_cluster_id, an empty ID of another cluster takes the ID of the alias of
+// cluster_id, and an alias is replaced by its ID. value returns the value of an attribute and whether it is known,
+// the unknown ones are left out.
+func (c *Client) resolveClusterIds(value func(attribute string) (string, bool), subClusters []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	alias, known := value("cluster_id")
+	if known {
+		id, err := c.resolveClusterId("cluster_id", alias)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			return nil, fmt.Errorf("cluster_id is required when the provider has no default_kafka_cluster_id")
+		}
+		ids["cluster_id"] = id
+		if alias == "" {
+			alias = c.defaultKafkaClusterId
+		}
+	}
+
+	for _, k := range subClusters {
+		v, ok := value(k)
+		if !ok || v == "" && !known {
+			continue
+		}
+		if _, ok := c.clusterAliases[alias]; ok && v == "" {
+			v = alias
+		}
+		id, err := c.resolveClusterId(k, v)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			return nil, fmt.Errorf("%s is required, unless cluster_id is an alias of the provider clusters with a %s", k, k)
+		}
+		ids[k] = id
+	}
+	return ids, nil
+}
+
+// setClusterIds sets the real IDs of the clusters of a resource being created, the IDs left unknown by
+// clusterIdsCustomizeDiff are resolved now
+func (c *Client) setClusterIds(d *schema.ResourceData, subClusters []string) (map[string]string, diag.Diagnostics) {
+	ids, err := c.resolveClusterIds(func(k string) (string, bool) { return d.Get(k).(string), true }, subClusters)
+	if err != nil {
+		return nil, errorDiags("Cannot resolve the cluster IDs", err, "cluster_id")
+	}
+	for k, id := range ids {
+		if err := d.Set(k, id); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+	return ids, nil
+}
+
+// clusterIdsCustomizeDiff plans the real IDs of the clusters with resolveClusterIds. The state only has real IDs, so
+// replacing an alias by its ID, or the other way around, is not a change, and changing the IDs of an alias replaces
+// its resources. An unknown cluster_id is left unknown, and so is an omitted one when the resource is created: the
+// plan cannot tell them apart, Create resolves them. subClusters returns the attributes of the other clusters the
+// resource needs.
+func clusterIdsCustomizeDiff(subClusters func(d *schema.ResourceDiff) []string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		c, ok := meta.(*Client)
+		if !ok {
+			return nil
+		}
+		var sub []string
+		if subClusters != nil {
+			sub = subClusters(d)
+		}
+
+		ids, err := c.resolveClusterIds(func(k string) (string, bool) { return d.Get(k).(string), d.NewValueKnown(k) }, sub)
+		if err != nil {
+			return err
+		}
+		for k, id := range ids {
+			if err := planClusterId(c, d, k, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// planClusterId plans the real ID of the cluster of an attribute. A state written before the aliases were resolved
+// can still have the alias, it is not replaced for its own ID and the next refresh sets the ID from the resource ID.
+func planClusterId(c *Client, d *schema.ResourceDiff, attribute, id string) error {
+	if d.Id() != "" {
+		old, _ := d.GetChange(attribute)
+		if old.(string) != id {
+			if oldId, err := c.resolveClusterId(attribute, old.(string)); err == nil && oldId == id {
+				return d.Clear(attribute)
+			}
+		}
+	}
+	if d.Get(attribute).(string) == id {
+		return nil
+	}
+	return d.SetNew(attribute, id)
+}
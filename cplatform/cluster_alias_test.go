@@ -0,0 +1,252 @@
+package cplatform
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// unknownValue is the value of an unknown attribute in the raw configurations, like a reference to another resource
+const unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+func fakeAliasClient() *Client {
+	return &Client{
+		defaultKafkaClusterId: "prod",
+		clusterAliases: map[string]map[string]string{
+			"prod": {"cluster_id": fakeClusterId, "schema_registry_cluster_id": "schema-registry"},
+			"dev":  {"cluster_id": "dev-cluster"},
+		},
+	}
+}
+
+func TestResolveClusterId(t *testing.T) {
+	cases := []struct {
+		attribute, value string
+		id, err          string
+	}{
+		{attribute: "cluster_id", value: "", id: fakeClusterId},
+		{attribute: "cluster_id", value: "dev", id: "dev-cluster"},
+		{attribute: "cluster_id", value: "lkc-123", id: "lkc-123"},
+		{attribute: "schema_registry_cluster_id", value: "prod", id: "schema-registry"},
+		{attribute: "schema_registry_cluster_id", value: "", id: ""},
+		{attribute: "schema_registry_cluster_id", value: "dev", err: `the cluster alias "dev" has no schema_registry_cluster_id`},
+	}
+	c := fakeAliasClient()
+	for _, tc := range cases {
+		id, err := c.resolveClusterId(tc.attribute, tc.value)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s = %q: %v", tc.attribute, tc.value, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s = %q: %v, expected %q", tc.attribute, tc.value, err, tc.err)
+		case id != tc.id:
+			t.Errorf("%s = %q resolved to %q, expected %q", tc.attribute, tc.value, id, tc.id)
+		}
+	}
+}
+
+func TestClusterIdsCustomizeDiff(t *testing.T) {
+	r := schemaRegistryRBAC()
+	config := map[string]interface{}{
+		"principal":    "User:alice",
+		"role":         "DeveloperRead",
+		"name":         "payments-",
+		"pattern_type": "PREFIXED",
+	}
+
+	// the omitted IDs are resolved at creation
+	diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"cluster_id", "schema_registry_cluster_id"} {
+		if a := diff.Attributes[k]; a == nil || !a.NewComputed {
+			t.Errorf("%s planned as %+v, expected it to be unknown", k, a)
+		}
+	}
+
+	// an alias of the configuration is planned as its ID
+	config["cluster_id"] = "prod"
+	diff, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := diff.Attributes["cluster_id"]; a == nil || a.New != fakeClusterId || a.NewComputed {
+		t.Errorf("cluster_id planned as %+v, expected %q", a, fakeClusterId)
+	}
+	delete(config, "cluster_id")
+
+	// the state has the IDs, the alias does not replace the binding
+	state := &terraform.InstanceState{
+		ID: buildId(fakeClusterId, "schema-registry", "User:alice", "DeveloperRead", "payments-", "PREFIXED"),
+		Attributes: map[string]string{
+			"id":                         buildId(fakeClusterId, "schema-registry", "User:alice", "DeveloperRead", "payments-", "PREFIXED"),
+			"principal":                  "User:alice",
+			"role":                       "DeveloperRead",
+			"name":                       "payments-",
+			"pattern_type":               "PREFIXED",
+			"cluster_id":                 fakeClusterId,
+			"schema_registry_cluster_id": "schema-registry",
+		},
+	}
+	config["cluster_id"] = "prod"
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Errorf("expected no change, got %+v", diff.Attributes)
+	}
+
+	// the alias of another cluster replaces the binding
+	config["cluster_id"] = "dev"
+	config["schema_registry_cluster_id"] = "schema-registry"
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := diff.Attributes["cluster_id"]; a == nil || a.New != "dev-cluster" || !diff.RequiresNew() {
+		t.Errorf("expected the binding to be replaced on dev-cluster, got %+v", diff)
+	}
+	delete(config, "schema_registry_cluster_id")
+
+	// an unknown cluster_id is left unknown, with the Schema Registry it would give
+	config["cluster_id"] = unknownValue
+	diff, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"cluster_id", "schema_registry_cluster_id"} {
+		if a := diff.Attributes[k]; a == nil || !a.NewComputed {
+			t.Errorf("%s planned as %+v, expected it to be unknown", k, a)
+		}
+	}
+
+	// the state has the alias, its ID does not replace the binding
+	state.Attributes["cluster_id"] = "prod"
+	config["cluster_id"] = fakeClusterId
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Errorf("expected no change, got %+v", diff.Attributes)
+	}
+
+	// a known alias without Schema Registry is rejected at plan
+	config["cluster_id"] = "dev"
+	config["schema_registry_cluster_id"] = "prod"
+	state.Attributes["cluster_id"] = fakeClusterId
+	_, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err != nil {
+		t.Errorf("expected the alias of the Schema Registry to be resolved, got %v", err)
+	}
+	config["schema_registry_cluster_id"] = "dev"
+	_, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), fakeAliasClient())
+	if err == nil || !strings.Contains(err.Error(), `"dev" has no schema_registry_cluster_id`) {
+		t.Errorf("expected the dev alias to be rejected, got %v", err)
+	}
+	delete(config, "schema_registry_cluster_id")
+
+	// the alias of a protected topic does not replace it, another cluster does
+	topic := &terraform.InstanceState{
+		ID: buildId(fakeClusterId, "payments"),
+		Attributes: map[string]string{
+			"id":                  buildId(fakeClusterId, "payments"),
+			"name":                "payments",
+			"partitions":          "1",
+			"replication_factor":  "3",
+			"cluster_id":          fakeClusterId,
+			"deletion_protection": "true",
+		},
+	}
+	topicConfig := map[string]interface{}{"name": "payments", "partitions": 1, "replication_factor": 3, "cluster_id": "prod", "deletion_protection": true}
+	diff, err = topics().Diff(context.Background(), topic, terraform.NewResourceConfigRaw(topicConfig), fakeAliasClient())
+	if err != nil || diff.RequiresNew() || diff.Attributes["cluster_id"] != nil {
+		t.Errorf("expected no change of cluster_id, got %+v, %v", diff, err)
+	}
+	topicConfig["cluster_id"] = "dev"
+	_, err = topics().Diff(context.Background(), topic, terraform.NewResourceConfigRaw(topicConfig), fakeAliasClient())
+	if err == nil || !strings.Contains(err.Error(), "cannot be replaced") {
+		t.Errorf("expected the protected topic not to be replaced, got %v", err)
+	}
+}
+
+func TestSetClusterIds(t *testing.T) {
+	sub := []string{"schema_registry_cluster_id"}
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		c      *Client
+		ids    map[string]string
+		err    string
+	}{
+		{
+			name:   "the default cluster is an alias, it gives both IDs",
+			config: map[string]interface{}{},
+			c:      fakeAliasClient(),
+			ids:    map[string]string{"cluster_id": fakeClusterId, "schema_registry_cluster_id": "schema-registry"},
+		},
+		{
+			name:   "the alias gives the omitted ID",
+			config: map[string]interface{}{"cluster_id": "prod"},
+			c:      fakeAliasClient(),
+			ids:    map[string]string{"cluster_id": fakeClusterId, "schema_registry_cluster_id": "schema-registry"},
+		},
+		{
+			name:   "the dev alias has no Schema Registry",
+			config: map[string]interface{}{"cluster_id": "dev"},
+			c:      fakeAliasClient(),
+			err:    `"dev" has no schema_registry_cluster_id`,
+		},
+		{
+			name:   "an explicit cluster_id has no Schema Registry",
+			config: map[string]interface{}{"cluster_id": "lkc-123"},
+			c:      fakeAliasClient(),
+			err:    "schema_registry_cluster_id is required",
+		},
+		{
+			name:   "without default_kafka_cluster_id, cluster_id is required",
+			config: map[string]interface{}{},
+			c:      &Client{},
+			err:    "cluster_id is required",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config["principal"], tc.config["role"], tc.config["name"], tc.config["pattern_type"] = "User:alice", "DeveloperRead", "payments-", "PREFIXED"
+			d := schema.TestResourceDataRaw(t, schemaRegistryRBAC().Schema, tc.config)
+			ids, diags := tc.c.setClusterIds(d, sub)
+			if tc.err != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary+diags[0].Detail, tc.err) {
+					t.Fatalf("expected %q, got %+v", tc.err, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			for k, want := range tc.ids {
+				if ids[k] != want || d.Get(k) != want {
+					t.Errorf("%s = %q, set to %v, expected %q", k, ids[k], d.Get(k), want)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterAliasesOf_duplicate(t *testing.T) {
+	block := map[string]interface{}{
+		"alias":                      "prod",
+		"kafka_cluster_id":           fakeClusterId,
+		"schema_registry_cluster_id": "",
+		"connect_cluster_id":         "",
+		"ksql_cluster_id":            "",
+	}
+	if _, err := clusterAliasesOf([]interface{}{block, block}); err == nil {
+		t.Error("expected an alias declared twice to be rejected")
+	}
+}
@@ -0,0 +1,172 @@
+package cplatform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Cluster Linking is only in the REST v3 API of Confluent Server, gonfluent does not wrap it
+
+type clusterLink struct {
+	LinkName             string   `json:"link_name"`
+	LinkId               string   `json:"link_id"`
+	SourceClusterId      string   `json:"source_cluster_id"`
+	DestinationClusterId string   `json:"destination_cluster_id"`
+	TopicNames           []string `json:"topic_names"`
+}
+
+type clusterLinkConfig struct {
+	Name      string  `json:"name"`
+	Value     *string `json:"value,omitempty"`
+	Operation string  `json:"operation,omitempty"`
+	Sensitive bool    `json:"sensitive,omitempty"`
+	ReadOnly  bool    `json:"read_only,omitempty"`
+	Source    string  `json:"source,omitempty"`
+}
+
+type mirrorTopic struct {
+	LinkName        string `json:"link_name"`
+	MirrorTopicName string `json:"mirror_topic_name"`
+	SourceTopicName string `json:"source_topic_name"`
+	NumPartitions   int    `json:"num_partitions"`
+	MirrorStatus    string `json:"mirror_status"`
+}
+
+func linkPath(clusterId, linkName string) string {
+	return "/kafka/v3/clusters/" + url.PathEscape(clusterId) + "/links/" + url.PathEscape(linkName)
+}
+
+// doRest sends in as JSON when it is not nil and decodes the response into out when it is not nil
+func doRest(c restAPI, method, u string, in, out interface{}) error {
+	var payloadBuf *bytes.Buffer
+	if in != nil {
+		payloadBuf = new(bytes.Buffer)
+		if err := json.NewEncoder(payloadBuf).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	var r []byte
+	var err error
+	if payloadBuf != nil {
+		r, err = c.DoRequest(method, u, payloadBuf)
+	} else {
+		r, err = c.DoRequest(method, u, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if out == nil || len(r) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r, out)
+}
+
+func createClusterLink(c restAPI, clusterId, linkName, sourceClusterId string, configs map[string]string) error {
+	body := struct {
+		SourceClusterId string              `json:"source_cluster_id"`
+		Configs         []clusterLinkConfig `json:"configs"`
+	}{
+		SourceClusterId: sourceClusterId,
+	}
+	for k, v := range configs {
+		v := v
+		body.Configs = append(body.Configs, clusterLinkConfig{Name: k, Value: &v})
+	}
+
+	u := "/kafka/v3/clusters/" + url.PathEscape(clusterId) + "/links?link_name=" + url.QueryEscape(linkName)
+	return doRest(c, "POST", u, body, nil)
+}
+
+func getClusterLink(c restAPI, clusterId, linkName string) (*clusterLink, error) {
+	var link clusterLink
+	if err := doRest(c, "GET", linkPath(clusterId, linkName), nil, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// listClusterLinkConfigs returns the configs of a link by name, the values of the sensitive configs are nil
+func listClusterLinkConfigs(c restAPI, clusterId, linkName string) (map[string]clusterLinkConfig, error) {
+	body := struct {
+		Data []clusterLinkConfig `json:"data"`
+	}{}
+	if err := doRest(c, "GET", linkPath(clusterId, linkName)+"/configs", nil, &body); err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]clusterLinkConfig)
+	for _, v := range body.Data {
+		configs[v.Name] = v
+	}
+	return configs, nil
+}
+
+// alterClusterLinkConfigs sets the given configs and resets the configs listed in remove to their default
+func alterClusterLinkConfigs(c restAPI, clusterId, linkName string, set map[string]string, remove []string) error {
+	body := struct {
+		Data []clusterLinkConfig `json:"data"`
+	}{}
+	for k, v := range set {
+		v := v
+		body.Data = append(body.Data, clusterLinkConfig{Name: k, Value: &v})
+	}
+	for _, k := range remove {
+		body.Data = append(body.Data, clusterLinkConfig{Name: k, Operation: "DELETE"})
+	}
+	if len(body.Data) == 0 {
+		return nil
+	}
+
+	return doRest(c, "PUT", linkPath(clusterId, linkName)+"/configs:alter", body, nil)
+}
+
+func deleteClusterLink(c restAPI, clusterId, linkName string) error {
+	return doRest(c, "DELETE", linkPath(clusterId, linkName), nil, nil)
+}
+
+func createMirrorTopic(c restAPI, clusterId, linkName, sourceTopic, mirrorTopic string) error {
+	body := struct {
+		SourceTopicName string `json:"source_topic_name"`
+		MirrorTopicName string `json:"mirror_topic_name,omitempty"`
+	}{
+		SourceTopicName: sourceTopic,
+		MirrorTopicName: mirrorTopic,
+	}
+	return doRest(c, "POST", linkPath(clusterId, linkName)+"/mirrors", body, nil)
+}
+
+func getMirrorTopic(c restAPI, clusterId, linkName, mirror string) (*mirrorTopic, error) {
+	var m mirrorTopic
+	if err := doRest(c, "GET", linkPath(clusterId, linkName)+"/mirrors/"+url.PathEscape(mirror), nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// mirrorTopicAction runs promote, failover, pause or resume on a mirror topic
+func mirrorTopicAction(c restAPI, clusterId, linkName, action, mirror string) error {
+	body := struct {
+		MirrorTopicNames []string `json:"mirror_topic_names"`
+	}{
+		MirrorTopicNames: []string{mirror},
+	}
+	results := struct {
+		Data []struct {
+			MirrorTopicName string  `json:"mirror_topic_name"`
+			ErrorMessage    *string `json:"error_message"`
+			ErrorCode       *int    `json:"error_code"`
+		} `json:"data"`
+	}{}
+	if err := doRest(c, "POST", linkPath(clusterId, linkName)+"/mirrors:"+action, body, &results); err != nil {
+		return err
+	}
+	for _, r := range results.Data {
+		if r.ErrorMessage != nil && *r.ErrorMessage != "" {
+			return fmt.Errorf("cannot %s mirror topic %s: %s", action, r.MirrorTopicName, *r.ErrorMessage)
+		}
+	}
+	return nil
+}
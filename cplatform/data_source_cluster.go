@@ -0,0 +1,315 @@
+package cplatform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// broker is a Kafka broker as returned by the Confluent REST v3 API
+type broker struct {
+	BrokerId int     `json:"broker_id"`
+	Host     string  `json:"host"`
+	Port     int     `json:"port"`
+	Rack     *string `json:"rack"`
+}
+
+// registeredCluster is a cluster registered in the MDS cluster registry
+// @ref https://docs.confluent.io/platform/current/security/cluster-registry.html
+type registeredCluster struct {
+	ClusterName string                   `json:"clusterName"`
+	Scope       confluent.ClusterDetails `json:"scope"`
+	Hosts       []struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	} `json:"hosts"`
+	Protocol string `json:"protocol"`
+}
+
+// dataSourceCluster looks up a Kafka cluster, its brokers and the clusters bound to it
+// example:
+//
+//	data "confluent_cluster" "main" {
+//	  provider = confluent-kafka.confluent
+//	}
+//
+//	resource "connectors_rbac" "example" {
+//	  cluster_id         = data.confluent_cluster.main.cluster_id
+//	  connect_cluster_id = data.confluent_cluster.main.connect_clusters[0].cluster_id
+//	  ...
+//	}
+func dataSourceCluster() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceClusterRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Description:   "The ID of cluster, the first cluster known by Confluent REST is used when neither cluster_id nor cluster_name is set",
+				ConflictsWith: []string{"cluster_name"},
+			},
+			"cluster_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Description:   "The name of the Kafka cluster in the MDS cluster registry",
+				ConflictsWith: []string{"cluster_id"},
+			},
+			"controller_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"brokers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"broker_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"rack": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"schema_registry_clusters": registeredClustersSchema("Schema Registry"),
+			"connect_clusters":         registeredClustersSchema("Kafka Connect"),
+			"ksql_clusters":            registeredClustersSchema("ksqlDB"),
+		},
+	}
+}
+
+func registeredClustersSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The " + kind + " clusters of the Kafka cluster in the MDS cluster registry",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cluster_name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"cluster_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	var diags diag.Diagnostics
+
+	registry, err := listRegisteredClusters(c.rest)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, logMDS, "Cannot read the cluster registry", map[string]interface{}{"error": err.Error()})
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Cannot read the MDS cluster registry",
+			Detail:   "cluster_name, schema_registry_clusters, connect_clusters and ksql_clusters will be empty: " + err.Error(),
+		})
+	}
+
+	clusterId := d.Get("cluster_id").(string)
+	clusterName := d.Get("cluster_name").(string)
+	switch {
+	case clusterName != "" && clusterId != "":
+		return append(diags, newDiagnostic(diag.Error, "Conflicting cluster_id and cluster_name",
+			"Only one of cluster_id and cluster_name can be set.", "cluster_name"))
+	case clusterName != "":
+		if err != nil {
+			return errorDiags("Cannot look up the Kafka cluster "+clusterName+" in the MDS cluster registry", err, "cluster_name")
+		}
+		if clusterId, err = kafkaClusterIdByName(registry, clusterName); err != nil {
+			return append(diags, newDiagnostic(diag.Error, "Kafka cluster not found", err.Error(), "cluster_name"))
+		}
+	case clusterId == "":
+		clusters, err := c.clusters.ListKafkaCluster()
+		if err != nil {
+			return append(diags, errorDiags("Cannot list the Kafka clusters", err, "cluster_id")...)
+		}
+		if len(clusters) == 0 {
+			return append(diags, newDiagnostic(diag.Error, "Kafka cluster not found", "The REST API has no Kafka cluster.", "cluster_id"))
+		}
+		clusterId = clusters[0].ClusterID
+	}
+
+	controllerId, err := getControllerId(c.rest, clusterId)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the cluster", map[string]interface{}{"cluster_id": clusterId, "error": err.Error()})
+		return append(diags, errorDiags("Cannot read the Kafka cluster "+clusterId, err, "cluster_id")...)
+	}
+
+	brokers, err := listBrokers(c.rest, clusterId)
+	if err != nil {
+		return append(diags, errorDiags("Cannot list the brokers of the Kafka cluster "+clusterId, err, "cluster_id")...)
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i].BrokerId < brokers[j].BrokerId })
+
+	b := make([]interface{}, 0, len(brokers))
+	for _, v := range brokers {
+		rack := ""
+		if v.Rack != nil {
+			rack = *v.Rack
+		}
+		b = append(b, map[string]interface{}{
+			"broker_id": v.BrokerId,
+			"host":      v.Host,
+			"port":      v.Port,
+			"rack":      rack,
+		})
+	}
+
+	var sr, connect, ksql []interface{}
+	for _, v := range registry {
+		s := v.Scope.Clusters
+		if s.KafkaCluster != clusterId {
+			continue
+		}
+		switch {
+		case isKafkaClusterScope(v.Scope):
+			clusterName = v.ClusterName
+		case s.SchemaRegistryCluster != "":
+			sr = append(sr, flattenRegisteredCluster(v.ClusterName, s.SchemaRegistryCluster))
+		case s.ConnectCluster != "":
+			connect = append(connect, flattenRegisteredCluster(v.ClusterName, s.ConnectCluster))
+		case s.KSqlCluster != "":
+			ksql = append(ksql, flattenRegisteredCluster(v.ClusterName, s.KSqlCluster))
+		}
+	}
+
+	d.SetId(clusterId)
+	if err := d.Set("cluster_id", clusterId); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("cluster_name", clusterName); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("controller_id", controllerId); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("brokers", b); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("schema_registry_clusters", sr); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("connect_clusters", connect); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("ksql_clusters", ksql); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// kafkaClusterIdByName returns the ID of the only Kafka cluster of the registry named name
+func kafkaClusterIdByName(registry []registeredCluster, name string) (string, error) {
+	var ids []string
+	for _, v := range registry {
+		if v.ClusterName == name && isKafkaClusterScope(v.Scope) && !contains(ids, v.Scope.Clusters.KafkaCluster) {
+			ids = append(ids, v.Scope.Clusters.KafkaCluster)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("the MDS cluster registry has no Kafka cluster named %s", name)
+	case 1:
+		return ids[0], nil
+	}
+	sort.Strings(ids)
+	return "", fmt.Errorf("the MDS cluster registry has %d Kafka clusters named %s: %s, set cluster_id instead", len(ids), name, strings.Join(ids, ", "))
+}
+
+func flattenRegisteredCluster(name, id string) map[string]interface{} {
+	return map[string]interface{}{
+		"cluster_name": name,
+		"cluster_id":   id,
+	}
+}
+
+func isKafkaClusterScope(scope confluent.ClusterDetails) bool {
+	s := scope.Clusters
+	return s.KafkaCluster != "" && s.SchemaRegistryCluster == "" && s.ConnectCluster == "" && s.KSqlCluster == ""
+}
+
+// getControllerId reads the ID of the controller from the link to the controller broker, eg: .../clusters/<id>/brokers/1
+// @ref https://docs.confluent.io/platform/current/kafka-rest/api.html#get--clusters-cluster_id
+func getControllerId(c restAPI, clusterId string) (int, error) {
+	r, err := c.DoRequest("GET", "/kafka/v3/clusters/"+clusterId, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	body := struct {
+		Controller confluent.Related `json:"controller"`
+	}{}
+	if err := json.Unmarshal(r, &body); err != nil {
+		return 0, err
+	}
+	if body.Controller.Related == "" {
+		return -1, nil
+	}
+
+	id, err := strconv.Atoi(path.Base(body.Controller.Related))
+	if err != nil {
+		return 0, fmt.Errorf("cannot read the controller of cluster %s: %s", clusterId, err)
+	}
+	return id, nil
+}
+
+// @ref https://docs.confluent.io/platform/current/kafka-rest/api.html#get--clusters-cluster_id-brokers
+func listBrokers(c restAPI, clusterId string) ([]broker, error) {
+	r, err := c.DoRequest("GET", "/kafka/v3/clusters/"+clusterId+"/brokers", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Data []broker `json:"data"`
+	}{}
+	if err := json.Unmarshal(r, &body); err != nil {
+		return nil, err
+	}
+	return body.Data, nil
+}
+
+// @ref https://docs.confluent.io/platform/current/security/cluster-registry.html#list-clusters
+func listRegisteredClusters(c restAPI) ([]registeredCluster, error) {
+	r, err := c.DoRequest("GET", "/security/1.0/registry/clusters", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []registeredCluster
+	if err := json.Unmarshal(r, &clusters); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
@@ -0,0 +1,31 @@
+package cplatform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKafkaClusterIdByName(t *testing.T) {
+	cluster := func(name, kafka, connect string) registeredCluster {
+		c := registeredCluster{ClusterName: name}
+		c.Scope.Clusters.KafkaCluster = kafka
+		c.Scope.Clusters.ConnectCluster = connect
+		return c
+	}
+	registry := []registeredCluster{
+		cluster("main", "kafka-1", ""),
+		cluster("main", "kafka-1", "connect-1"),
+		cluster("dr", "kafka-2", ""),
+		cluster("dr", "kafka-3", ""),
+	}
+
+	if id, err := kafkaClusterIdByName(registry, "main"); err != nil || id != "kafka-1" {
+		t.Errorf("main = %s, %v", id, err)
+	}
+	if _, err := kafkaClusterIdByName(registry, "dr"); err == nil || !strings.Contains(err.Error(), "2 Kafka clusters named dr: kafka-2, kafka-3") {
+		t.Errorf("expected the name of two clusters to be rejected, got %v", err)
+	}
+	if _, err := kafkaClusterIdByName(registry, "staging"); err == nil || !strings.Contains(err.Error(), "no Kafka cluster named staging") {
+		t.Errorf("expected an unknown name to be rejected, got %v", err)
+	}
+}
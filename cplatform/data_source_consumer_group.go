@@ -0,0 +1,259 @@
+package cplatform
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceConsumerGroup reads the state, the members and the lag of a consumer group
+// example:
+//
+//	data "kafka_consumer_group" "app" {
+//	  group_id = "app"
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// The lag of a partition is its log end offset minus the offset committed by the group.
+func dataSourceConsumerGroup() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceConsumerGroupRead,
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"topic": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the offsets of this topic",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Stable, PreparingRebalance, CompletingRebalance, Empty or Dead",
+			},
+			"protocol_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"protocol": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The partition assignor of the group, for example range",
+			},
+			"members": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"member_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"client_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"client_host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"assignments": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"topic": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"partitions": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem: &schema.Schema{
+											Type: schema.TypeInt,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"offsets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"topic": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"partition": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"committed_offset": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"log_end_offset": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"lag": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"total_lag": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceConsumerGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	group := d.Get("group_id").(string)
+
+	g, err := c.describeConsumerGroup(group)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot describe the consumer group", map[string]interface{}{"group_id": group, "error": err.Error()})
+		return errorDiags("Cannot describe the consumer group "+group, err, "group_id")
+	}
+
+	var topicPartitions map[string][]int32
+	if topic, ok := d.GetOk("topic"); ok {
+		partitions, err := c.kafka.Partitions(topic.(string))
+		if err != nil {
+			return errorDiags("Cannot read the partitions of the topic "+topic.(string), err, "topic")
+		}
+		topicPartitions = map[string][]int32{topic.(string): partitions}
+	}
+	committed, err := c.committedOffsets(group, topicPartitions)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the offsets of the consumer group", map[string]interface{}{"group_id": group, "error": err.Error()})
+		return errorDiags("Cannot read the offsets of the consumer group "+group, err, "group_id")
+	}
+
+	offsets, totalLag, err := consumerGroupLag(c.kafka, committed)
+	if err != nil {
+		return errorDiags("Cannot read the lag of the consumer group "+group, err, "group_id")
+	}
+
+	d.SetId(group)
+	if err := d.Set("state", g.State); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("protocol_type", g.ProtocolType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("protocol", g.Protocol); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("members", flattenGroupMembers(g.Members)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("offsets", offsets); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("total_lag", totalLag); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// consumerGroupLag returns the committed offset, the log end offset and the lag of each partition sorted by topic
+// and partition, and the sum of the lags
+func consumerGroupLag(kafka sarama.Client, committed map[string]map[int32]int64) ([]interface{}, int, error) {
+	topics := make([]string, 0, len(committed))
+	for t := range committed {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	var offsets []interface{}
+	var totalLag int64
+	for _, t := range topics {
+		partitions := make([]int32, 0, len(committed[t]))
+		for p := range committed[t] {
+			partitions = append(partitions, p)
+		}
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+		for _, p := range partitions {
+			end, err := kafka.GetOffset(t, p, sarama.OffsetNewest)
+			if err != nil {
+				return nil, 0, err
+			}
+			lag := end - committed[t][p]
+			if lag < 0 {
+				lag = 0
+			}
+			totalLag += lag
+			offsets = append(offsets, map[string]interface{}{
+				"topic":            t,
+				"partition":        int(p),
+				"committed_offset": int(committed[t][p]),
+				"log_end_offset":   int(end),
+				"lag":              int(lag),
+			})
+		}
+	}
+	return offsets, int(totalLag), nil
+}
+
+// flattenGroupMembers sorts the members by ID, the assignments of the groups which are not consumer groups
+// (Connect workers for example) are left empty
+func flattenGroupMembers(members map[string]*sarama.GroupMemberDescription) []interface{} {
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]interface{}, 0, len(members))
+	for _, id := range ids {
+		m := members[id]
+
+		var assignments []interface{}
+		if a, err := m.GetMemberAssignment(); err == nil && a != nil {
+			topics := make([]string, 0, len(a.Topics))
+			for t := range a.Topics {
+				topics = append(topics, t)
+			}
+			sort.Strings(topics)
+			for _, t := range topics {
+				partitions := make([]int, 0, len(a.Topics[t]))
+				for _, p := range a.Topics[t] {
+					partitions = append(partitions, int(p))
+				}
+				sort.Ints(partitions)
+				assignments = append(assignments, map[string]interface{}{
+					"topic":      t,
+					"partitions": partitions,
+				})
+			}
+		}
+
+		result = append(result, map[string]interface{}{
+			"member_id":   id,
+			"client_id":   m.ClientId,
+			"client_host": m.ClientHost,
+			"assignments": assignments,
+		})
+	}
+	return result
+}
@@ -0,0 +1,218 @@
+package cplatform
+
+import (
+	"context"
+	"sort"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// principalBinding is a role held by a principal, directly or through one of its groups
+type principalBinding struct {
+	Role         string
+	ClusterType  string
+	ClusterId    string
+	GrantedTo    string
+	ResourceType string
+	Name         string
+	PatternType  string
+}
+
+// dataSourceRBACPrincipalBindings returns every role binding of a principal
+// example:
+//
+//	data "rbac_principal_bindings" "app" {
+//	  principal  = "User:app"
+//	  cluster_id = "kafka-cluster-id"
+//
+//	  provider = confluent-kafka.confluent
+//	}
+func dataSourceRBACPrincipalBindings() *schema.Resource {
+	s := rbacScopeSchema()
+	s["principal"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		Description:  "The principal, User:<name> or Group:<name>, the name can be an LDAP DN",
+		ValidateFunc: validatePrincipal,
+	}
+	s["roles"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The distinct roles held by the principal",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+	s["bindings"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"role": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"cluster_type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"cluster_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The ID of the Kafka, Schema Registry, Connect or ksqlDB cluster of the binding",
+				},
+				"granted_to": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The principal the role is bound to, it is a group when the role is inherited",
+				},
+				"resource_type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Empty for the cluster-level bindings",
+				},
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"pattern_type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceRBACPrincipalBindingsRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceRBACPrincipalBindingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	principal := normalizePrincipal(d.Get("principal").(string))
+
+	scopes, diags := rbacScopes(ctx, c.rest, d)
+
+	seen := make(map[principalBinding]struct{})
+	var bindings []principalBinding
+	add := func(b principalBinding) {
+		if _, ok := seen[b]; !ok {
+			seen[b] = struct{}{}
+			bindings = append(bindings, b)
+		}
+	}
+
+	for _, scope := range scopes {
+		roles, err := lookupPrincipalRoleNames(c.rest, principal, scope.Details)
+		if err != nil {
+			tflog.SubsystemError(ctx, logMDS, "Cannot look up the roles of the principal", map[string]interface{}{
+				"principal":    principal,
+				"cluster_type": scope.ClusterType,
+				"cluster_id":   scope.ClusterId,
+				"error":        err.Error(),
+			})
+			return append(diags, errorDiags("Cannot look up the roles of "+principal, err, "principal")...)
+		}
+
+		for _, role := range roles {
+			patterns, err := c.roleBindings.LookupRoleBinding(principal, role, scope.Details)
+			if err != nil {
+				return append(diags, errorDiags("Cannot look up the role binding of "+principal+" to "+role, err, "principal")...)
+			}
+			for _, b := range expandPrincipalBindings(scope, principal, role, patterns) {
+				add(b)
+			}
+		}
+
+		// the direct lookup does not return the roles inherited from the groups of the principal
+		resources, err := lookupPrincipalResources(c.rest, principal, scope.Details)
+		if err != nil {
+			return append(diags, errorDiags("Cannot look up the resources of "+principal, err, "principal")...)
+		}
+		for grantedTo, byRole := range resources {
+			for role, patterns := range byRole {
+				for _, b := range expandPrincipalBindings(scope, grantedTo, role, patterns) {
+					add(b)
+				}
+			}
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		a, b := bindings[i], bindings[j]
+		if a.ClusterType != b.ClusterType {
+			return a.ClusterType < b.ClusterType
+		}
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.GrantedTo != b.GrantedTo {
+			return a.GrantedTo < b.GrantedTo
+		}
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		return a.Name < b.Name
+	})
+
+	roles := make([]interface{}, 0)
+	r := make(map[string]struct{})
+	for _, b := range bindings {
+		if _, ok := r[b.Role]; !ok {
+			r[b.Role] = struct{}{}
+			roles = append(roles, b.Role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].(string) < roles[j].(string) })
+
+	d.SetId(buildId(d.Get("cluster_id").(string), principal))
+	if err := d.Set("roles", roles); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if err := d.Set("bindings", flattenPrincipalBindings(bindings)); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// expandPrincipalBindings returns a binding per resource pattern, or a single binding without resource for the cluster-level roles
+func expandPrincipalBindings(scope rbacScope, grantedTo, role string, patterns []confluent.ResourcePattern) []principalBinding {
+	b := principalBinding{
+		Role:        role,
+		ClusterType: scope.ClusterType,
+		ClusterId:   scope.ClusterId,
+		GrantedTo:   grantedTo,
+	}
+	if len(patterns) == 0 {
+		return []principalBinding{b}
+	}
+
+	r := make([]principalBinding, 0, len(patterns))
+	for _, p := range patterns {
+		b.ResourceType = p.ResourceType
+		b.Name = p.Name
+		b.PatternType = p.PatternType
+		r = append(r, b)
+	}
+	return r
+}
+
+func flattenPrincipalBindings(bindings []principalBinding) []interface{} {
+	r := make([]interface{}, 0, len(bindings))
+	for _, b := range bindings {
+		r = append(r, map[string]interface{}{
+			"role":          b.Role,
+			"cluster_type":  b.ClusterType,
+			"cluster_id":    b.ClusterId,
+			"granted_to":    b.GrantedTo,
+			"resource_type": b.ResourceType,
+			"name":          b.Name,
+			"pattern_type":  b.PatternType,
+		})
+	}
+	return r
+}
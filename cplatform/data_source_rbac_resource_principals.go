@@ -0,0 +1,205 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceRBACResourcePrincipals returns the principals which hold a role on a resource
+// example:
+//
+//	data "rbac_resource_principals" "payments_writers" {
+//	  cluster_id    = "kafka-cluster-id"
+//	  cluster_type  = "Kafka"
+//	  resource_type = "Topic"
+//	  name          = "payments-events"
+//	  roles         = ["DeveloperWrite", "ResourceOwner"]
+//
+//	  provider = confluent-kafka.confluent
+//	}
+func dataSourceRBACResourcePrincipals() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRBACResourcePrincipalsRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Kafka",
+				ValidateFunc: validation.StringInSlice(validCluster, false),
+			},
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of cluster",
+			},
+			"schema_registry_cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of cluster",
+			},
+			"connect_cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of cluster",
+			},
+			"ksql_cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of cluster",
+			},
+			"resource_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The type of the resource, eg: Topic, Group, Subject, Connector",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the resource, the principals bound with a matching PREFIXED pattern are returned as well",
+			},
+			"roles": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The roles to look up, default to every resource-level role",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"principals": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The distinct principals which hold any of the roles",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"role_principals": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"principals": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRBACResourcePrincipalsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	matrix := meta.(*Client).roleMatrix()
+	c := meta.(*Client).rest
+	clusterType := d.Get("cluster_type").(string)
+	resourceType := d.Get("resource_type").(string)
+	name := d.Get("name").(string)
+
+	cd, err := clusterDetailsOfType(d, clusterType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	roles := scopeRole
+	if v := d.Get("roles").([]interface{}); len(v) > 0 {
+		roles = make([]string, 0, len(v))
+		for _, r := range v {
+			// the custom roles of MDS are known with refresh_roles, without it they are looked up unchecked
+			if _, ok := matrix[r.(string)]; !ok && meta.(*Client).roles == nil {
+				diags = append(diags, warningDiags("Unchecked role "+r.(string), r.(string)+" is not a predefined role, set refresh_roles to check the custom roles.", "roles")...)
+				roles = append(roles, r.(string))
+				continue
+			}
+			if err := validateRoleScope(matrix, r.(string), clusterType, resourceType); err != nil {
+				return errorDiags("Invalid role "+r.(string), err, "roles")
+			}
+			roles = append(roles, r.(string))
+		}
+	}
+
+	all := make([]interface{}, 0)
+	seen := make(map[string]struct{})
+	byRole := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		principals, err := lookupResourcePrincipals(c, role, resourceType, name, cd)
+		if err != nil {
+			tflog.SubsystemError(ctx, logMDS, "Cannot look up the principals of the resource", map[string]interface{}{
+				"role":          role,
+				"resource_type": resourceType,
+				"name":          name,
+				"error":         err.Error(),
+			})
+			return errorDiags("Cannot look up the principals of "+resourceType+" "+name, err, "name")
+		}
+		sort.Strings(principals)
+
+		p := make([]interface{}, 0, len(principals))
+		for _, v := range principals {
+			p = append(p, v)
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				all = append(all, v)
+			}
+		}
+		byRole = append(byRole, map[string]interface{}{
+			"role":       role,
+			"principals": p,
+		})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].(string) < all[j].(string) })
+
+	d.SetId(buildId(d.Get("cluster_id").(string), clusterType, resourceType, name))
+	if err := d.Set("principals", all); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("role_principals", byRole); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// clusterDetailsOfType builds the scope of a cluster_type the same way as cluster_role_binding
+func clusterDetailsOfType(d *schema.ResourceData, clusterType string) (confluent.ClusterDetails, error) {
+	cd := confluent.ClusterDetails{}
+	cd.Clusters.KafkaCluster = d.Get("cluster_id").(string)
+
+	f, err := filterClusterTypeWithClusterId(d)
+	if err != nil {
+		return cd, err
+	}
+
+	switch clusterType {
+	case "Kafka":
+	case "SchemaRegistry":
+		if !(contains(f, "schema_registry_cluster_id")) {
+			return cd, fmt.Errorf("miss parameter: schema_registry_cluster_id")
+		}
+		cd.Clusters.SchemaRegistryCluster = d.Get("schema_registry_cluster_id").(string)
+	case "Connect":
+		if !(contains(f, "connect_cluster_id")) {
+			return cd, fmt.Errorf("miss parameter: connect_cluster_id")
+		}
+		cd.Clusters.ConnectCluster = d.Get("connect_cluster_id").(string)
+	case "KSQL":
+		if !(contains(f, "ksql_cluster_id")) {
+			return cd, fmt.Errorf("miss parameter: ksql_cluster_id")
+		}
+		cd.Clusters.KSqlCluster = d.Get("ksql_cluster_id").(string)
+	}
+
+	return cd, nil
+}
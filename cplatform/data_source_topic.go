@@ -0,0 +1,252 @@
+package cplatform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// topicReplica is a replica of a partition as returned by the replica status of the Confluent REST v3 API
+type topicReplica struct {
+	PartitionId int  `json:"partition_id"`
+	BrokerId    int  `json:"broker_id"`
+	IsLeader    bool `json:"is_leader"`
+	IsInSync    bool `json:"is_in_isr"`
+}
+
+// dataSourceTopic reads an existing topic, including topics which are not managed by this workspace
+// example:
+//
+//	data "kafka_topic" "payments" {
+//	  cluster_id = "kafka-cluster-id" # Optional: an ID or an alias, default_kafka_cluster_id if not set
+//	  name       = "payments"
+//
+//	  provider = confluent-kafka.confluent
+//	}
+func dataSourceTopic() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceTopicRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the topic.",
+			},
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of cluster, or an alias of the provider clusters. default_kafka_cluster_id if not set",
+			},
+			"partitions": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of partitions.",
+			},
+			"replication_factor": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of replication factors.",
+			},
+			"is_internal": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"config": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "A map of every config of the topic, including the defaults.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"config_entries": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Every config of the topic with the place it comes from.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "One of default, dynamic or static.",
+						},
+						"is_read_only": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"is_sensitive": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"partition": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"partition_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"leader": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The broker ID of the leader, -1 if the partition has no leader.",
+						},
+						"replicas": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"isr": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The broker IDs of the in-sync replicas.",
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTopicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	clusterId, diags := c.clusterIdOf(d, "cluster_id")
+	if diags != nil {
+		return diags
+	}
+	topicName := d.Get("name").(string)
+
+	topic, err := c.topics.GetTopic(clusterId, topicName)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the topic", map[string]interface{}{"cluster_id": clusterId, "topic": topicName, "error": err.Error()})
+		return errorDiags("Cannot read the topic "+topicName, err, "name")
+	}
+
+	// GetTopic already fetched the partitions with GetTopicPartitions
+	p := topic.PartitionsDetails
+	sort.Slice(p, func(i, j int) bool { return p[i].PartitionId < p[j].PartitionId })
+
+	replicas, err := c.topicReplicas(clusterId, topicName)
+	if err != nil {
+		return errorDiags("Cannot read the replicas of the topic "+topicName, err, "name")
+	}
+	partitions := make([]interface{}, 0, len(p))
+	for _, v := range p {
+		partitions = append(partitions, flattenPartitionReplicas(v.PartitionId, replicas[v.PartitionId]))
+	}
+
+	config := make(map[string]interface{}, len(topic.Config))
+	entries := make([]interface{}, 0, len(topic.Config))
+	for _, v := range topic.Config {
+		config[v.Name] = v.Value
+		entries = append(entries, map[string]interface{}{
+			"name":         v.Name,
+			"value":        v.Value,
+			"source":       configSource(v.Source),
+			"is_read_only": v.IsReadOnly,
+			"is_sensitive": v.IsSensitive,
+		})
+	}
+
+	d.SetId(buildId(clusterId, topicName))
+	if err := d.Set("cluster_id", clusterId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("partitions", len(p)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("replication_factor", int(topic.ReplicationFactor)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("is_internal", topic.IsInternal); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("config", config); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("config_entries", entries); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("partition", partitions); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// getTopicReplicas lists the replicas of every partition of a topic in one request, by partition ID.
+// gonfluent only returns the partition IDs.
+// @ref https://docs.confluent.io/platform/current/kafka-rest/api.html#get--clusters-cluster_id-topics-topic_name-partitions---replica-status
+func getTopicReplicas(c restAPI, clusterId, topicName string) (map[int][]topicReplica, error) {
+	u := fmt.Sprintf("/kafka/v3/clusters/%s/topics/%s/partitions/-/replica-status", clusterId, topicName)
+	r, err := c.DoRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Data []topicReplica `json:"data"`
+	}{}
+	if err := json.Unmarshal(r, &body); err != nil {
+		return nil, err
+	}
+	replicas := make(map[int][]topicReplica)
+	for _, v := range body.Data {
+		replicas[v.PartitionId] = append(replicas[v.PartitionId], v)
+	}
+	return replicas, nil
+}
+
+func flattenPartitionReplicas(partitionId int, replicas []topicReplica) map[string]interface{} {
+	leader := -1
+	all := make([]interface{}, 0, len(replicas))
+	isr := make([]interface{}, 0, len(replicas))
+	for _, r := range replicas {
+		all = append(all, r.BrokerId)
+		if r.IsInSync {
+			isr = append(isr, r.BrokerId)
+		}
+		if r.IsLeader {
+			leader = r.BrokerId
+		}
+	}
+
+	return map[string]interface{}{
+		"partition_id": partitionId,
+		"leader":       leader,
+		"replicas":     all,
+		"isr":          isr,
+	}
+}
+
+// configSource simplifies the config source reported by Kafka, eg: DYNAMIC_TOPIC_CONFIG, STATIC_BROKER_CONFIG
+func configSource(source string) string {
+	switch {
+	case source == "DEFAULT_CONFIG":
+		return "default"
+	case strings.HasPrefix(source, "DYNAMIC_"):
+		return "dynamic"
+	case strings.HasPrefix(source, "STATIC_"):
+		return "static"
+	}
+	return strings.ToLower(source)
+}
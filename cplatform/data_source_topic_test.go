@@ -0,0 +1,120 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestDataSourceTopic_defaultCluster(t *testing.T) {
+	f := newFakeConfluent(t)
+	f.topics[fakeClusterId+"|payments"] = &fakeTopic{partitions: 2, replicationFactor: 2, configs: map[string]string{"cleanup.policy": "compact"}}
+	c, err := f.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.defaultKafkaClusterId = "prod"
+	c.clusterAliases = map[string]map[string]string{"prod": {"cluster_id": fakeClusterId}}
+
+	d := dataSourceTopic().TestResourceData()
+	if err := d.Set("name", "payments"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := dataSourceTopicRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Id() != fakeClusterId+"|payments" || d.Get("cluster_id") != fakeClusterId {
+		t.Errorf("read the topic %s of the cluster %s", d.Id(), d.Get("cluster_id"))
+	}
+	want := []interface{}{
+		map[string]interface{}{"partition_id": 0, "leader": 0, "replicas": []interface{}{0, 1}, "isr": []interface{}{0, 1}},
+		map[string]interface{}{"partition_id": 1, "leader": 1, "replicas": []interface{}{1, 2}, "isr": []interface{}{1, 2}},
+	}
+	if got := d.Get("partition"); !reflect.DeepEqual(got, want) {
+		t.Errorf("partition = %v, want %v", got, want)
+	}
+}
+
+func TestDataSourceTopics_filtersInId(t *testing.T) {
+	f := newFakeConfluent(t)
+	for _, name := range []string{"payments", "payments-dlq", "orders"} {
+		f.topics[fakeClusterId+"|"+name] = &fakeTopic{partitions: 1, replicationFactor: 1, configs: map[string]string{}}
+	}
+	c, err := f.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]bool)
+	for _, prefix := range []string{"payments", "orders"} {
+		d := dataSourceTopics().TestResourceData()
+		if err := d.Set("cluster_id", fakeClusterId); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Set("prefix", prefix); err != nil {
+			t.Fatal(err)
+		}
+		if diags := dataSourceTopicsRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("read: %v", diags)
+		}
+		ids[d.Id()] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("the data sources with other prefixes have the same ID: %v", ids)
+	}
+
+	// without default_kafka_cluster_id, cluster_id is required
+	d := dataSourceTopics().TestResourceData()
+	if diags := dataSourceTopicsRead(context.Background(), d, c); !diags.HasError() {
+		t.Error("expected an error without cluster_id")
+	}
+}
+
+func TestDataSourceTopic_adminAPI(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 2, 2, nil)
+	k.createTopic("orders", 1, 1, nil)
+	c := k.client(t, "admin")
+	// only the Kafka protocol is used
+	c.rest = noRestAPI{}
+
+	d := dataSourceTopic().TestResourceData()
+	for key, v := range map[string]interface{}{"cluster_id": fakeClusterId, "name": "payments"} {
+		if err := d.Set(key, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diags := dataSourceTopicRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	want := []interface{}{
+		map[string]interface{}{"partition_id": 0, "leader": 1, "replicas": []interface{}{1, 2}, "isr": []interface{}{1, 2}},
+		map[string]interface{}{"partition_id": 1, "leader": 2, "replicas": []interface{}{2, 3}, "isr": []interface{}{2, 3}},
+	}
+	if got := d.Get("partition"); !reflect.DeepEqual(got, want) {
+		t.Errorf("partition = %v, want %v", got, want)
+	}
+
+	d = dataSourceTopics().TestResourceData()
+	if err := d.Set("cluster_id", fakeClusterId); err != nil {
+		t.Fatal(err)
+	}
+	if diags := dataSourceTopicsRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if got := d.Get("names"); !reflect.DeepEqual(got, []interface{}{"orders", "payments"}) {
+		t.Errorf("names = %v", got)
+	}
+	if got := d.Get("topics.1.partitions"); got != 2 {
+		t.Errorf("payments has %v partitions, expected 2", got)
+	}
+}
+
+// noRestAPI fails every request to the REST API
+type noRestAPI struct{}
+
+func (noRestAPI) DoRequest(method string, uri string, _ io.Reader) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected request %s %s", method, uri)
+}
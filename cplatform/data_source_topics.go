@@ -0,0 +1,167 @@
+package cplatform
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// topicSummary is a topic in the list of topics of the Confluent REST v3 API,
+// gonfluent's ListTopics drops the partitions count
+type topicSummary struct {
+	Name              string `json:"topic_name"`
+	IsInternal        bool   `json:"is_internal"`
+	ReplicationFactor int    `json:"replication_factor"`
+	PartitionsCount   int    `json:"partitions_count"`
+}
+
+// dataSourceTopics lists the topics of a cluster
+// example:
+//
+//	data "kafka_topics" "platform" {
+//	  cluster_id = "kafka-cluster-id" # Optional: an ID or an alias, default_kafka_cluster_id if not set
+//	  prefix     = "system-platform-"
+//
+//	  provider = confluent-kafka.confluent
+//	}
+func dataSourceTopics() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceTopicsRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of cluster, or an alias of the provider clusters. default_kafka_cluster_id if not set",
+			},
+			"prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the topics whose name starts with this prefix.",
+			},
+			"regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only return the topics whose name matches this regular expression.",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"include_internal": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Also return the internal topics, eg: __consumer_offsets.",
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"topics": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"partitions": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"replication_factor": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"is_internal": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTopicsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	clusterId, diags := c.clusterIdOf(d, "cluster_id")
+	if diags != nil {
+		return diags
+	}
+	prefix := d.Get("prefix").(string)
+	includeInternal := d.Get("include_internal").(bool)
+
+	var re *regexp.Regexp
+	if v := d.Get("regex").(string); v != "" {
+		re = regexp.MustCompile(v)
+	}
+
+	all, err := c.topicSummaries(clusterId)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot list the topics", map[string]interface{}{"cluster_id": clusterId, "error": err.Error()})
+		return errorDiags("Cannot list the topics of the Kafka cluster "+clusterId, err, "cluster_id")
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	names := make([]interface{}, 0, len(all))
+	topics := make([]interface{}, 0, len(all))
+	for _, t := range all {
+		if t.IsInternal && !includeInternal {
+			continue
+		}
+		if !strings.HasPrefix(t.Name, prefix) {
+			continue
+		}
+		if re != nil && !re.MatchString(t.Name) {
+			continue
+		}
+		names = append(names, t.Name)
+		topics = append(topics, map[string]interface{}{
+			"name":               t.Name,
+			"partitions":         t.PartitionsCount,
+			"replication_factor": t.ReplicationFactor,
+			"is_internal":        t.IsInternal,
+		})
+	}
+
+	// the filters are in the ID, two data sources of the same cluster with other filters have other IDs
+	d.SetId(buildId(clusterId, prefix, d.Get("regex").(string), strconv.FormatBool(includeInternal)))
+	if err := d.Set("cluster_id", clusterId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("topics", topics); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// @ref https://docs.confluent.io/platform/current/kafka-rest/api.html#get--clusters-cluster_id-topics
+func listTopics(c restAPI, clusterId string) ([]topicSummary, error) {
+	r, err := c.DoRequest("GET", "/kafka/v3/clusters/"+clusterId+"/topics", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Data []topicSummary `json:"data"`
+	}{}
+	if err := json.Unmarshal(r, &body); err != nil {
+		return nil, err
+	}
+	return body.Data, nil
+}
@@ -0,0 +1,160 @@
+package cplatform
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+var (
+	// apiErrorPattern matches the errors of gonfluent for the responses of MDS and the REST API,
+	// "error with status: " + status + " " + message
+	apiErrorPattern = regexp.MustCompile(`error with status: (\d{3})(.*)`)
+
+	// apiErrorHints explain the status codes of MDS and the REST API
+	apiErrorHints = map[int]string{
+		http.StatusUnauthorized: "The username or the password of the provider is wrong, or its token has expired.",
+		http.StatusForbidden:    "The principal of the provider is not allowed to do it: the role bindings need SecurityAdmin or UserAdmin, the topics ResourceOwner or ClusterAdmin.",
+		http.StatusNotFound:     "It does not exist, or one of the cluster IDs is wrong.",
+		http.StatusConflict:     "It already exists.",
+	}
+
+	// topicErrorAttributes are the attributes of kafka_topic the errors of Kafka are about
+	topicErrorAttributes = map[sarama.KError]string{
+		sarama.ErrInvalidTopic:             "name",
+		sarama.ErrTopicAlreadyExists:       "name",
+		sarama.ErrUnknownTopicOrPartition:  "name",
+		sarama.ErrInvalidPartitions:        "partitions",
+		sarama.ErrInvalidReplicationFactor: "replication_factor",
+		sarama.ErrInvalidReplicaAssignment: "replication_factor",
+		sarama.ErrReassignmentInProgress:   "replication_factor",
+		sarama.ErrInvalidConfig:            "config",
+	}
+
+	// kafkaErrorHints explain the errors of Kafka
+	kafkaErrorHints = map[sarama.KError]string{
+		sarama.ErrTopicAuthorizationFailed:   "The principal of the provider is not allowed to do it on the topic.",
+		sarama.ErrClusterAuthorizationFailed: "The principal of the provider is not allowed to do it on the cluster.",
+		sarama.ErrGroupAuthorizationFailed:   "The principal of the provider is not allowed to do it on the consumer group.",
+		sarama.ErrPolicyViolation:            "The request is rejected by the create topic policy of the brokers.",
+		sarama.ErrReassignmentInProgress:     "The partitions of the topic are already being reassigned, retry once it is over.",
+		sarama.ErrInvalidReplicationFactor:   "The replication factor cannot be larger than the number of brokers.",
+	}
+)
+
+// apiError is an error of MDS or the REST API, gonfluent only keeps the status and the message in the error
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+// parseAPIError returns the status code and the message of an error of MDS or the REST API
+func parseAPIError(err error) (*apiError, bool) {
+	m := apiErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil, false
+	}
+	code, _ := strconv.Atoi(m[1])
+	message := strings.TrimSpace(m[2])
+	message = strings.TrimSpace(strings.TrimPrefix(message, http.StatusText(code)))
+	return &apiError{StatusCode: code, Message: message}, true
+}
+
+// kafkaErrorCode returns the error code of Kafka in err
+func kafkaErrorCode(err error) (sarama.KError, bool) {
+	var kerr sarama.KError
+	if errors.As(err, &kerr) {
+		return kerr, true
+	}
+	var topicErr *sarama.TopicError
+	if errors.As(err, &topicErr) {
+		return topicErr.Err, true
+	}
+	var partitionErr *sarama.TopicPartitionError
+	if errors.As(err, &partitionErr) {
+		return partitionErr.Err, true
+	}
+	return sarama.ErrNoError, false
+}
+
+// isNotFound tells if err is a 404 of MDS or the REST API, or an unknown topic of Kafka
+func isNotFound(err error) bool {
+	if errors.Is(err, errTopicNotFound) {
+		return true
+	}
+	if e, ok := parseAPIError(err); ok {
+		return e.StatusCode == http.StatusNotFound
+	}
+	if code, ok := kafkaErrorCode(err); ok {
+		return code == sarama.ErrUnknownTopicOrPartition
+	}
+	return false
+}
+
+// errorDetail explains err with the status code of MDS and the REST API, or with the error code of Kafka
+func errorDetail(err error) string {
+	if e, ok := parseAPIError(err); ok {
+		detail := fmt.Sprintf("Confluent Server returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+		if e.Message != "" {
+			detail += ": " + e.Message
+		}
+		if hint, ok := apiErrorHints[e.StatusCode]; ok {
+			detail += "\n\n" + hint
+		}
+		return detail
+	}
+	if code, ok := kafkaErrorCode(err); ok {
+		detail := fmt.Sprintf("Kafka returned the error code %d: %s", int16(code), err)
+		if hint, ok := kafkaErrorHints[code]; ok {
+			detail += "\n\n" + hint
+		}
+		return detail
+	}
+	return err.Error()
+}
+
+// errorDiags returns the error diagnostic of err, pointing at attribute when it is not empty
+func errorDiags(summary string, err error, attribute string) diag.Diagnostics {
+	return diag.Diagnostics{newDiagnostic(diag.Error, summary, errorDetail(err), attribute)}
+}
+
+// topicErrorDiags is errorDiags for kafka_topic, the errors of Kafka about one of its attributes point at this attribute instead
+func topicErrorDiags(summary string, err error, attribute string) diag.Diagnostics {
+	if code, ok := kafkaErrorCode(err); ok {
+		if a, ok := topicErrorAttributes[code]; ok {
+			attribute = a
+		}
+	}
+	return errorDiags(summary, err, attribute)
+}
+
+// warningDiags returns a warning, pointing at attribute when it is not empty
+func warningDiags(summary, detail, attribute string) diag.Diagnostics {
+	return diag.Diagnostics{newDiagnostic(diag.Warning, summary, detail, attribute)}
+}
+
+func newDiagnostic(severity diag.Severity, summary, detail, attribute string) diag.Diagnostic {
+	d := diag.Diagnostic{
+		Severity: severity,
+		Summary:  summary,
+		Detail:   detail,
+	}
+	if attribute != "" {
+		d.AttributePath = cty.GetAttrPath(attribute)
+	}
+	return d
+}
+
+// roleAlreadyBoundDiags is the error of a resource already in the role binding of a principal when it is created,
+// the binding is imported instead so that Terraform does not remove a binding it did not add
+func roleAlreadyBoundDiags(principal, role, resourceType, name, id string) diag.Diagnostics {
+	detail := fmt.Sprintf("%s already has the role %s on %s %s. Bring the binding under Terraform with terraform import and the ID %q.", principal, role, resourceType, name, id)
+	return errorDiags("Role already bound", errors.New(detail), "name")
+}
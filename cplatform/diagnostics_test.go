@@ -0,0 +1,64 @@
+package cplatform
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestParseAPIError(t *testing.T) {
+	e, ok := parseAPIError(errors.New(`error with status: 403 Forbidden {"status_code":403,"message":"Access denied"}`))
+	if !ok || e.StatusCode != 403 || e.Message != `{"status_code":403,"message":"Access denied"}` {
+		t.Errorf("parseAPIError() = %+v, %v", e, ok)
+	}
+	if _, ok := parseAPIError(errors.New("dial tcp: connection refused")); ok {
+		t.Error("expected an error without status not to be parsed")
+	}
+}
+
+func TestErrorDiags(t *testing.T) {
+	diags := errorDiags("Cannot create the role binding", errors.New("error with status: 403 Forbidden"), "role")
+	if len(diags) != 1 || diags[0].Severity != diag.Error {
+		t.Fatalf("errorDiags() = %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "returned 403 Forbidden") || !strings.Contains(diags[0].Detail, "SecurityAdmin") {
+		t.Errorf("detail %q does not explain the status", diags[0].Detail)
+	}
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("role")) {
+		t.Errorf("the error is about %v, expected role", diags[0].AttributePath)
+	}
+
+	// the errors of Kafka point at the attribute of the topic they are about
+	err := fmt.Errorf("cannot create the partitions: %w", &sarama.TopicError{Err: sarama.ErrInvalidPartitions})
+	diags = topicErrorDiags("Cannot update the topic", err, "name")
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("partitions")) {
+		t.Errorf("the error is about %v, expected partitions", diags[0].AttributePath)
+	}
+	if !strings.Contains(diags[0].Detail, "error code 37") {
+		t.Errorf("detail %q does not have the error code of Kafka", diags[0].Detail)
+	}
+
+	// the other resources keep their attribute, the topic of kafka_consumer_group_offsets is not name
+	err = fmt.Errorf("cannot read the partitions: %w", sarama.ErrUnknownTopicOrPartition)
+	diags = errorDiags("Cannot read the partitions of the topic payments", err, "topic")
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("topic")) {
+		t.Errorf("the error is about %v, expected topic", diags[0].AttributePath)
+	}
+
+	diags = errorDiags("Cannot connect to Kafka", errors.New("no brokers"), "")
+	if diags[0].AttributePath != nil || diags[0].Detail != "no brokers" {
+		t.Errorf("errorDiags() = %+v", diags[0])
+	}
+}
+
+func TestRoleAlreadyBoundDiags(t *testing.T) {
+	diags := roleAlreadyBoundDiags("User:alice", "DeveloperRead", "Topic", "payments", "lkc|User:alice|DeveloperRead|Topic|payments|LITERAL")
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "terraform import") {
+		t.Errorf("expected an error pointing to terraform import, got %v", diags)
+	}
+}
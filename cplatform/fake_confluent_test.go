@@ -0,0 +1,660 @@
+package cplatform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	fakeClusterId = "fake-kafka-cluster"
+	fakeUsername  = "admin"
+	fakePassword  = "admin-secret"
+	fakeToken     = "fake-token"
+)
+
+// fakeRoles are the roles of the fake MDS, an older MDS returning accessPolicy and a custom role
+var fakeRoles = json.RawMessage(`[
+  {"name": "SystemAdmin", "accessPolicy": {"scopeType": "Cluster", "allowedOperations": [{"resourceType": "All", "operations": ["All"]}]}},
+  {"name": "DeveloperRead", "accessPolicy": {"scopeType": "Resource", "allowedOperations": [{"resourceType": "Topic", "operations": ["Read"]}, {"resourceType": "Group", "operations": ["Read"]}]}},
+  {"name": "SubjectReader", "policies": [{"bindWithResource": true, "allowedOperations": [{"resourceType": "Subject", "operations": ["Read"]}]}]}
+]`)
+
+// fakeConfluent is an in-process Confluent Server serving, from memory, the MDS and REST v3 endpoints used by the
+// provider. It only checks what the provider relies on: the bearer token, the scopes and the existence of the objects.
+type fakeConfluent struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// topics by cluster_id|topic_name
+	topics map[string]*fakeTopic
+	// bindings by principal|role|scope, the patterns of a cluster role binding are empty
+	bindings map[string][]confluent.ResourcePattern
+	// links by cluster_id|link_name, mirrors by cluster_id|link_name|mirror_topic
+	links   map[string]*fakeLink
+	mirrors map[string]*mirrorTopic
+
+	// beforeRequest is run before serving every request, without the lock
+	beforeRequest func()
+}
+
+type fakeTopic struct {
+	partitions        int
+	replicationFactor int
+	configs           map[string]string
+}
+
+type fakeLink struct {
+	link    clusterLink
+	configs map[string]string
+}
+
+func newFakeConfluent(t *testing.T) *fakeConfluent {
+	f := &fakeConfluent{
+		topics:   make(map[string]*fakeTopic),
+		bindings: make(map[string][]confluent.ResourcePattern),
+		links:    make(map[string]*fakeLink),
+		mirrors:  make(map[string]*mirrorTopic),
+	}
+	f.Server = httptest.NewServer(f)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// client returns the meta of the provider connected to the fake, with the REST backend for the topics
+func (f *fakeConfluent) client() (*Client, error) {
+	httpClient := confluent.NewDefaultHttpClient(f.URL, fakeUsername, fakePassword)
+	httpClient.UserAgent = UserAgent
+	client := confluent.NewClient(httpClient, nil, nil)
+	token, err := client.Login()
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Token = token
+
+	c := &Client{}
+	c.useGonfluent(client, "rest")
+	return c, nil
+}
+
+// providerFactories returns the provider "confluent" configured against the fake, refresh_roles reads the roles of the fake
+func (f *fakeConfluent) providerFactories() map[string]func() (*schema.Provider, error) {
+	return map[string]func() (*schema.Provider, error){
+		"confluent": func() (*schema.Provider, error) {
+			p := Provider()
+			p.ConfigureContextFunc = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+				c, err := f.client()
+				if err != nil {
+					return nil, diag.FromErr(err)
+				}
+				if d.Get("refresh_roles").(bool) {
+					roles, err := listRoles(c.rest)
+					if err != nil {
+						return nil, diag.FromErr(err)
+					}
+					c.roles = roleMatrixOf(roles)
+				}
+				return c, nil
+			}
+			return p, nil
+		},
+	}
+}
+
+// testAccFakePreCheck skips the test when there is no terraform binary to run it
+func testAccFakePreCheck(t *testing.T) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("terraform is not installed, set TF_ACC_TERRAFORM_PATH to run the tests against the fake Confluent Server")
+	}
+}
+
+// fakeProviderConfig is the provider block of the test configurations, every resource sets provider = confluent
+const fakeProviderConfig = `
+provider "confluent" {
+  username          = "admin"
+  password          = "admin-secret"
+  bootstrap_servers = ["localhost:9093"]
+}
+`
+
+func (f *fakeConfluent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.beforeRequest != nil {
+		f.beforeRequest()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/security/1.0/authenticate" {
+		if u, p, ok := r.BasicAuth(); !ok || u != fakeUsername || p != fakePassword {
+			fakeError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+		fakeReply(w, http.StatusOK, confluent.Authenticate{AuthToken: fakeToken, TokenType: "Bearer", ExpiresIn: 3600})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeToken {
+		fakeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+
+	p := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(p) >= 6 && p[0] == "security" && p[2] == "principals" && p[4] == "roles":
+		f.serveRoles(w, r, p[3], p[5], p[6:])
+	case len(p) >= 4 && p[0] == "security" && p[2] == "lookup":
+		f.serveLookup(w, r, p[3:])
+	case r.URL.Path == "/security/1.0/registry/clusters":
+		fakeReply(w, http.StatusOK, []registeredCluster{})
+	case r.URL.Path == rolesPath && r.Method == "GET":
+		fakeReply(w, http.StatusOK, fakeRoles)
+	case len(p) >= 4 && p[0] == "kafka" && p[1] == "v3" && p[2] == "clusters":
+		f.serveCluster(w, r, p[3], p[4:])
+	default:
+		fakeError(w, http.StatusNotFound, "no endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *fakeConfluent) serveRoles(w http.ResponseWriter, r *http.Request, principal, role string, p []string) {
+	switch {
+	// cluster role bindings
+	case len(p) == 0:
+		var cd confluent.ClusterDetails
+		if !fakeDecode(w, r, &cd) {
+			return
+		}
+		k := bindingKey(principal, role, cd)
+		switch r.Method {
+		case "POST":
+			if _, ok := f.bindings[k]; !ok {
+				f.bindings[k] = []confluent.ResourcePattern{}
+			}
+		case "DELETE":
+			delete(f.bindings, k)
+		default:
+			fakeError(w, http.StatusMethodNotAllowed, r.Method)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case len(p) == 1 && p[0] == "resources" && r.Method == "POST":
+		var cd confluent.ClusterDetails
+		if !fakeDecode(w, r, &cd) {
+			return
+		}
+		patterns := f.bindings[bindingKey(principal, role, cd)]
+		if patterns == nil {
+			patterns = []confluent.ResourcePattern{}
+		}
+		fakeReply(w, http.StatusOK, patterns)
+
+	// resource role bindings
+	case len(p) == 1 && p[0] == "bindings":
+		var rb confluent.RoleBinding
+		if !fakeDecode(w, r, &rb) {
+			return
+		}
+		k := bindingKey(principal, role, rb.Scope)
+		switch r.Method {
+		case "POST":
+			for _, v := range rb.ResourcePatterns {
+				if !containsPattern(f.bindings[k], v) {
+					f.bindings[k] = append(f.bindings[k], v)
+				}
+			}
+		case "DELETE":
+			var patterns []confluent.ResourcePattern
+			for _, v := range f.bindings[k] {
+				if !containsPattern(rb.ResourcePatterns, v) {
+					patterns = append(patterns, v)
+				}
+			}
+			f.bindings[k] = patterns
+			if len(patterns) == 0 {
+				delete(f.bindings, k)
+			}
+		case "PUT":
+			f.bindings[k] = rb.ResourcePatterns
+		default:
+			fakeError(w, http.StatusMethodNotAllowed, r.Method)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		fakeError(w, http.StatusNotFound, "no endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *fakeConfluent) serveLookup(w http.ResponseWriter, r *http.Request, p []string) {
+	var cd confluent.ClusterDetails
+	if !fakeDecode(w, r, &cd) {
+		return
+	}
+	scope := scopeKey(cd)
+
+	switch {
+	case len(p) == 3 && p[0] == "principals" && p[2] == "roleNames":
+		roles := []string{}
+		for k := range f.bindings {
+			principal, role, s := splitBindingKey(k)
+			if principal == p[1] && s == scope {
+				roles = append(roles, role)
+			}
+		}
+		sort.Strings(roles)
+		fakeReply(w, http.StatusOK, roles)
+
+	case len(p) == 3 && p[0] == "principal" && p[2] == "resources":
+		resources := make(map[string]map[string][]confluent.ResourcePattern)
+		for k, patterns := range f.bindings {
+			principal, role, s := splitBindingKey(k)
+			if principal != p[1] || s != scope || len(patterns) == 0 {
+				continue
+			}
+			if resources[principal] == nil {
+				resources[principal] = make(map[string][]confluent.ResourcePattern)
+			}
+			resources[principal][role] = patterns
+		}
+		fakeReply(w, http.StatusOK, resources)
+
+	case len(p) == 6 && p[0] == "role" && p[2] == "resource" && p[4] == "name":
+		principals := []string{}
+		for k, patterns := range f.bindings {
+			principal, role, s := splitBindingKey(k)
+			if role != p[1] || s != scope {
+				continue
+			}
+			for _, v := range patterns {
+				if v.ResourceType == p[3] && (v.Name == p[5] || v.PatternType == "PREFIXED" && strings.HasPrefix(p[5], v.Name)) {
+					principals = append(principals, principal)
+					break
+				}
+			}
+		}
+		sort.Strings(principals)
+		fakeReply(w, http.StatusOK, principals)
+
+	default:
+		fakeError(w, http.StatusNotFound, "no endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *fakeConfluent) serveCluster(w http.ResponseWriter, r *http.Request, clusterId string, p []string) {
+	if clusterId != fakeClusterId {
+		fakeError(w, http.StatusNotFound, "cluster "+clusterId+" not found")
+		return
+	}
+
+	switch {
+	case len(p) >= 1 && p[0] == "topics":
+		f.serveTopics(w, r, clusterId, p[1:])
+	case len(p) == 1 && p[0] == "links" && r.Method == "POST":
+		body := struct {
+			SourceClusterId string              `json:"source_cluster_id"`
+			Configs         []clusterLinkConfig `json:"configs"`
+		}{}
+		if !fakeDecode(w, r, &body) {
+			return
+		}
+		name := r.URL.Query().Get("link_name")
+		if _, ok := f.links[clusterId+"|"+name]; ok || name == "" {
+			fakeError(w, http.StatusBadRequest, "cluster link "+name+" already exists")
+			return
+		}
+		l := &fakeLink{
+			link: clusterLink{
+				LinkName:             name,
+				LinkId:               "link-id-" + name,
+				SourceClusterId:      body.SourceClusterId,
+				DestinationClusterId: clusterId,
+			},
+			configs: make(map[string]string),
+		}
+		for _, v := range body.Configs {
+			l.configs[v.Name] = *v.Value
+		}
+		f.links[clusterId+"|"+name] = l
+		w.WriteHeader(http.StatusNoContent)
+	case len(p) >= 2 && p[0] == "links":
+		f.serveLink(w, r, clusterId, p[1], p[2:])
+	default:
+		fakeError(w, http.StatusNotFound, "no endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *fakeConfluent) serveTopics(w http.ResponseWriter, r *http.Request, clusterId string, p []string) {
+	if len(p) == 0 {
+		switch r.Method {
+		case "GET":
+			body := struct {
+				Data []map[string]interface{} `json:"data"`
+			}{Data: []map[string]interface{}{}}
+			for k, t := range f.topics {
+				if name := strings.TrimPrefix(k, clusterId+"|"); name != k {
+					body.Data = append(body.Data, fakeTopicJSON(clusterId, name, t))
+				}
+			}
+			fakeReply(w, http.StatusOK, body)
+		case "POST":
+			var topic confluent.Topic
+			if !fakeDecode(w, r, &topic) {
+				return
+			}
+			if _, ok := f.topics[clusterId+"|"+topic.Name]; ok {
+				fakeError(w, http.StatusBadRequest, "topic "+topic.Name+" already exists")
+				return
+			}
+			t := &fakeTopic{
+				partitions:        int(topic.Partitions),
+				replicationFactor: int(topic.ReplicationFactor),
+				configs:           make(map[string]string),
+			}
+			if t.replicationFactor <= 0 {
+				t.replicationFactor = 3
+			}
+			for _, v := range topic.Config {
+				t.configs[v.Name] = v.Value
+			}
+			f.topics[clusterId+"|"+topic.Name] = t
+			fakeReply(w, http.StatusCreated, fakeTopicJSON(clusterId, topic.Name, t))
+		default:
+			fakeError(w, http.StatusMethodNotAllowed, r.Method)
+		}
+		return
+	}
+
+	name, sub := p[0], p[1:]
+	t, ok := f.topics[clusterId+"|"+name]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "This server does not host this topic-partition.")
+		return
+	}
+
+	switch {
+	case len(sub) == 0 && r.Method == "GET":
+		fakeReply(w, http.StatusOK, fakeTopicJSON(clusterId, name, t))
+	case len(sub) == 0 && r.Method == "PATCH":
+		body := struct {
+			PartitionsCount int `json:"partitions_count"`
+		}{}
+		if !fakeDecode(w, r, &body) {
+			return
+		}
+		if body.PartitionsCount < t.partitions {
+			fakeError(w, http.StatusBadRequest, "the number of partitions can only be increased")
+			return
+		}
+		t.partitions = body.PartitionsCount
+		fakeReply(w, http.StatusOK, fakeTopicJSON(clusterId, name, t))
+	case len(sub) == 0 && r.Method == "DELETE":
+		delete(f.topics, clusterId+"|"+name)
+		// deleting a mirror topic removes it from its link
+		for k, m := range f.mirrors {
+			if strings.HasPrefix(k, clusterId+"|") && m.MirrorTopicName == name {
+				delete(f.mirrors, k)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(sub) == 1 && sub[0] == "partitions" && r.Method == "GET":
+		body := struct {
+			Data []confluent.Partition `json:"data"`
+		}{Data: []confluent.Partition{}}
+		for i := 0; i < t.partitions; i++ {
+			body.Data = append(body.Data, confluent.Partition{ClusterID: clusterId, TopicName: name, PartitionId: i})
+		}
+		fakeReply(w, http.StatusOK, body)
+	// broker i+r leads the replica r of partition i, every replica is in sync
+	case len(sub) == 3 && sub[0] == "partitions" && sub[1] == "-" && sub[2] == "replica-status" && r.Method == "GET":
+		body := struct {
+			Data []topicReplica `json:"data"`
+		}{Data: []topicReplica{}}
+		for i := 0; i < t.partitions; i++ {
+			for r := 0; r < t.replicationFactor; r++ {
+				body.Data = append(body.Data, topicReplica{PartitionId: i, BrokerId: i + r, IsLeader: r == 0, IsInSync: true})
+			}
+		}
+		fakeReply(w, http.StatusOK, body)
+	case len(sub) == 1 && sub[0] == "configs" && r.Method == "GET":
+		body := struct {
+			Data []confluent.TopicConfig `json:"data"`
+		}{Data: []confluent.TopicConfig{}}
+		for k, v := range t.configs {
+			body.Data = append(body.Data, confluent.TopicConfig{ClusterId: clusterId, TopicName: name, Name: k, Value: v, Source: "DYNAMIC_TOPIC_CONFIG"})
+		}
+		sort.Slice(body.Data, func(i, j int) bool { return body.Data[i].Name < body.Data[j].Name })
+		fakeReply(w, http.StatusOK, body)
+	case len(sub) == 1 && sub[0] == "configs:alter" && r.Method == "POST":
+		body := struct {
+			Data []struct {
+				Name      string `json:"name"`
+				Value     string `json:"value"`
+				Operation string `json:"operation"`
+			} `json:"data"`
+		}{}
+		if !fakeDecode(w, r, &body) {
+			return
+		}
+		for _, v := range body.Data {
+			if strings.EqualFold(v.Operation, "DELETE") {
+				delete(t.configs, v.Name)
+			} else {
+				t.configs[v.Name] = v.Value
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeError(w, http.StatusNotFound, "no endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (f *fakeConfluent) serveLink(w http.ResponseWriter, r *http.Request, clusterId, name string, p []string) {
+	l, ok := f.links[clusterId+"|"+name]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "cluster link "+name+" not found")
+		return
+	}
+	prefix := clusterId + "|" + name + "|"
+
+	switch {
+	case len(p) == 0 && r.Method == "GET":
+		link := l.link
+		link.TopicNames = []string{}
+		for k, m := range f.mirrors {
+			if strings.HasPrefix(k, prefix) {
+				link.TopicNames = append(link.TopicNames, m.MirrorTopicName)
+			}
+		}
+		fakeReply(w, http.StatusOK, link)
+	case len(p) == 0 && r.Method == "DELETE":
+		// the promoted and failed-over mirror topics are not mirrored anymore
+		for k, m := range f.mirrors {
+			if strings.HasPrefix(k, prefix) && m.MirrorStatus != "STOPPED" {
+				fakeError(w, http.StatusBadRequest, "cluster link "+name+" has mirror topics")
+				return
+			}
+		}
+		delete(f.links, clusterId+"|"+name)
+		w.WriteHeader(http.StatusNoContent)
+	case len(p) == 1 && p[0] == "configs" && r.Method == "GET":
+		body := struct {
+			Data []clusterLinkConfig `json:"data"`
+		}{Data: []clusterLinkConfig{}}
+		for k, v := range l.configs {
+			v := v
+			c := clusterLinkConfig{Name: k, Value: &v, Source: "DYNAMIC_CLUSTER_LINK_CONFIG"}
+			if k == "sasl.jaas.config" {
+				c.Value = nil
+				c.Sensitive = true
+			}
+			body.Data = append(body.Data, c)
+		}
+		fakeReply(w, http.StatusOK, body)
+	case len(p) == 1 && p[0] == "configs:alter" && r.Method == "PUT":
+		body := struct {
+			Data []clusterLinkConfig `json:"data"`
+		}{}
+		if !fakeDecode(w, r, &body) {
+			return
+		}
+		for _, v := range body.Data {
+			if v.Operation == "DELETE" {
+				delete(l.configs, v.Name)
+			} else {
+				l.configs[v.Name] = *v.Value
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(p) == 1 && p[0] == "mirrors" && r.Method == "POST":
+		body := struct {
+			SourceTopicName string `json:"source_topic_name"`
+			MirrorTopicName string `json:"mirror_topic_name"`
+		}{}
+		if !fakeDecode(w, r, &body) {
+			return
+		}
+		mirror := body.MirrorTopicName
+		if mirror == "" {
+			mirror = body.SourceTopicName
+		}
+		if _, ok := f.topics[clusterId+"|"+mirror]; ok {
+			fakeError(w, http.StatusBadRequest, "topic "+mirror+" already exists")
+			return
+		}
+		f.topics[clusterId+"|"+mirror] = &fakeTopic{partitions: 3, replicationFactor: 3, configs: make(map[string]string)}
+		f.mirrors[prefix+mirror] = &mirrorTopic{
+			LinkName:        name,
+			MirrorTopicName: mirror,
+			SourceTopicName: body.SourceTopicName,
+			NumPartitions:   3,
+			MirrorStatus:    "ACTIVE",
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(p) == 2 && p[0] == "mirrors" && r.Method == "GET":
+		m, ok := f.mirrors[prefix+p[1]]
+		if !ok {
+			fakeError(w, http.StatusNotFound, "mirror topic "+p[1]+" not found")
+			return
+		}
+		fakeReply(w, http.StatusOK, m)
+	case len(p) == 1 && strings.HasPrefix(p[0], "mirrors:") && r.Method == "POST":
+		body := struct {
+			MirrorTopicNames []string `json:"mirror_topic_names"`
+		}{}
+		if !fakeDecode(w, r, &body) {
+			return
+		}
+		status := map[string]string{
+			"pause":    "PAUSED",
+			"resume":   "ACTIVE",
+			"promote":  "STOPPED",
+			"failover": "STOPPED",
+		}[strings.TrimPrefix(p[0], "mirrors:")]
+		results := struct {
+			Data []map[string]interface{} `json:"data"`
+		}{}
+		for _, mirror := range body.MirrorTopicNames {
+			result := map[string]interface{}{"mirror_topic_name": mirror}
+			if m, ok := f.mirrors[prefix+mirror]; !ok || status == "" {
+				result["error_code"] = http.StatusBadRequest
+				result["error_message"] = "cannot run " + p[0] + " on " + mirror
+			} else {
+				m.MirrorStatus = status
+			}
+			results.Data = append(results.Data, result)
+		}
+		fakeReply(w, http.StatusOK, results)
+	default:
+		fakeError(w, http.StatusNotFound, "no endpoint "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// hasBinding returns whether the principal holds the role at the scope, on the resource when pattern is not nil
+func (f *fakeConfluent) hasBinding(principal, role string, cd confluent.ClusterDetails, pattern *confluent.ResourcePattern) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	patterns, ok := f.bindings[bindingKey(principal, role, cd)]
+	if pattern == nil {
+		return ok
+	}
+	return containsPattern(patterns, *pattern)
+}
+
+func (f *fakeConfluent) topic(name string) (fakeTopic, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t, ok := f.topics[fakeClusterId+"|"+name]
+	if !ok {
+		return fakeTopic{}, false
+	}
+	return *t, true
+}
+
+func bindingKey(principal, role string, cd confluent.ClusterDetails) string {
+	return principal + "|" + role + "|" + scopeKey(cd)
+}
+
+func splitBindingKey(k string) (string, string, string) {
+	p := strings.SplitN(k, "|", 3)
+	return p[0], p[1], p[2]
+}
+
+// scopeKey ignores the cluster name, the scopes are only given by their cluster IDs here
+func scopeKey(cd confluent.ClusterDetails) string {
+	b, _ := json.Marshal(cd.Clusters)
+	return string(b)
+}
+
+func containsPattern(patterns []confluent.ResourcePattern, p confluent.ResourcePattern) bool {
+	for _, v := range patterns {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+func fakeTopicJSON(clusterId, name string, t *fakeTopic) map[string]interface{} {
+	return map[string]interface{}{
+		"cluster_id":         clusterId,
+		"topic_name":         name,
+		"is_internal":        false,
+		"replication_factor": t.replicationFactor,
+		"partitions_count":   t.partitions,
+	}
+}
+
+func fakeDecode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		fakeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %s", err))
+		return false
+	}
+	return true
+}
+
+func fakeReply(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, message string) {
+	fakeReply(w, status, confluent.ErrorResponse{StatusCode: status, ErrorCode: status, Message: message})
+}
@@ -0,0 +1,715 @@
+package cplatform
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// fakeBrokers is the number of brokers of fakeKafka, the first one is the controller
+const fakeBrokers = 3
+
+// fakeKafka is an in-process Kafka cluster for the code using sarama.
+// gonfluent and the admin backend use sarama through the sarama.Client and sarama.ClusterAdmin interfaces,
+// which fakeKafka implements over an in-memory state. The requests sent to a broker directly, CreatePartitions,
+// IncrementalAlterConfigs and OffsetCommit, go to a sarama MockBroker and are applied from its history before every read.
+// fakeKafka is also the clientQuotaAdmin of the meta.
+// The REST view of the topics of fakeConfluent is kept in sync.
+type fakeKafka struct {
+	mock    *sarama.MockBroker
+	config  *sarama.Config
+	brokers []*sarama.Broker
+	rest    *fakeConfluent
+
+	mu sync.Mutex
+	// applied is the number of requests of the history of mock already applied
+	applied int
+	topics  map[string]*kafkaTopic
+	// reassignments are the target replicas by topic and partition, completed after reassignPolls listings
+	reassignments map[string]map[int32][]int32
+	reassignPolls int
+	// listings is the number of ListPartitionReassignments
+	listings int
+	// brokerConfigs are the dynamic configs by broker ID, "" for the cluster-wide default
+	brokerConfigs map[string]map[string]string
+	// scram are the SCRAM credentials by user and mechanism
+	scram map[string]map[sarama.ScramMechanismType]sarama.AlterUserScramCredentialsUpsert
+	// groups are the offsets committed by group, topic and partition
+	groups map[string]map[string]map[int32]int64
+	// quotas are the client quotas by quota ID
+	quotas map[string]map[string]float64
+}
+
+type kafkaTopic struct {
+	replicas [][]int32
+	configs  map[string]string
+	// ends are the end offsets of the partitions, the messages are not kept
+	ends map[int32]int64
+}
+
+func newFakeKafka(t *testing.T, rest *fakeConfluent) *fakeKafka {
+	mock := sarama.NewMockBroker(t, 1)
+	mock.SetHandlerByMap(map[string]sarama.MockResponse{
+		"CreatePartitionsRequest":        sarama.NewMockCreatePartitionsResponse(t),
+		"IncrementalAlterConfigsRequest": sarama.NewMockWrapper(&sarama.IncrementalAlterConfigsResponse{}),
+		"OffsetCommitRequest":            sarama.NewMockOffsetCommitResponse(t),
+	})
+	t.Cleanup(mock.Close)
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_7_0_0
+
+	k := &fakeKafka{
+		mock:          mock,
+		config:        config,
+		rest:          rest,
+		topics:        make(map[string]*kafkaTopic),
+		reassignments: make(map[string]map[int32][]int32),
+		reassignPolls: 1,
+		brokerConfigs: make(map[string]map[string]string),
+		scram:         make(map[string]map[sarama.ScramMechanismType]sarama.AlterUserScramCredentialsUpsert),
+		groups:        make(map[string]map[string]map[int32]int64),
+		quotas:        make(map[string]map[string]float64),
+	}
+	for i := 0; i < fakeBrokers; i++ {
+		// every broker is the mock broker, only the controller is opened
+		k.brokers = append(k.brokers, sarama.NewBroker(mock.Addr()))
+	}
+	if err := k.brokers[0].Open(config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = k.brokers[0].Close() })
+
+	if rest != nil {
+		rest.beforeRequest = k.sync
+	}
+	return k
+}
+
+// client returns the meta of the provider with the given topic_api, using fakeConfluent for REST
+func (k *fakeKafka) client(t *testing.T, api string) *Client {
+	httpClient := confluent.NewDefaultHttpClient(k.rest.URL, fakeUsername, fakePassword)
+	httpClient.Token = fakeToken
+
+	kafka := fakeSaramaClient{k: k}
+	admin := fakeClusterAdmin{k: k}
+	c := &Client{kafka: kafka}
+	c.adminOnce.Do(func() {
+		c.admin, c.adminKafka = admin, kafka
+	})
+	c.useGonfluent(confluent.NewClient(httpClient, fakeGonfluentClient{kafka}, admin), api)
+	c.clientQuotas = k
+	return c
+}
+
+// providerFactories returns the provider "confluent" configured against the fake, with the default topic_api
+func (k *fakeKafka) providerFactories(t *testing.T) map[string]func() (*schema.Provider, error) {
+	return map[string]func() (*schema.Provider, error){
+		"confluent": func() (*schema.Provider, error) {
+			p := Provider()
+			p.ConfigureContextFunc = func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
+				return k.client(t, ""), nil
+			}
+			return p, nil
+		},
+	}
+}
+
+// createTopic creates a topic with the replicas of the partitions on consecutive brokers
+func (k *fakeKafka) createTopic(name string, partitions, replicationFactor int, configs map[string]string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	topic := &kafkaTopic{configs: make(map[string]string), ends: make(map[int32]int64)}
+	for p := 0; p < partitions; p++ {
+		topic.replicas = append(topic.replicas, k.assign(p, replicationFactor))
+	}
+	for key, v := range configs {
+		topic.configs[key] = v
+	}
+	k.topics[name] = topic
+	k.publish(name)
+}
+
+// produce moves the end offset of the partition as if messages were produced
+func (k *fakeKafka) produce(topic string, partition int32, messages int64) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.topics[topic].ends[partition] += messages
+}
+
+func (k *fakeKafka) topic(name string) (kafkaTopic, bool) {
+	k.sync()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	t, ok := k.topics[name]
+	if !ok {
+		return kafkaTopic{}, false
+	}
+	return *t, true
+}
+
+// requests returns the requests received by the mock broker of the given type
+func (k *fakeKafka) requests(match func(interface{}) bool) int {
+	n := 0
+	for _, r := range k.mock.History() {
+		if match(r.Request) {
+			n++
+		}
+	}
+	return n
+}
+
+func (k *fakeKafka) assign(partition, replicationFactor int) []int32 {
+	replicas := make([]int32, 0, replicationFactor)
+	for i := 0; i < replicationFactor; i++ {
+		replicas = append(replicas, int32((partition+i)%fakeBrokers+1))
+	}
+	return replicas
+}
+
+// sync applies the requests received by the mock broker since the last sync
+func (k *fakeKafka) sync() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	history := k.mock.History()
+	for _, r := range history[k.applied:] {
+		switch req := r.Request.(type) {
+		case *sarama.CreatePartitionsRequest:
+			for name, tp := range req.TopicPartitions {
+				topic, ok := k.topics[name]
+				if !ok {
+					continue
+				}
+				for p := len(topic.replicas); p < int(tp.Count); p++ {
+					topic.replicas = append(topic.replicas, k.assign(p, len(topic.replicas[0])))
+				}
+				k.publish(name)
+			}
+		case *sarama.IncrementalAlterConfigsRequest:
+			for _, res := range req.Resources {
+				if res.Type == sarama.BrokerResource {
+					if k.brokerConfigs[res.Name] == nil {
+						k.brokerConfigs[res.Name] = make(map[string]string)
+					}
+					alterConfigs(k.brokerConfigs[res.Name], res.ConfigEntries)
+					continue
+				}
+				topic, ok := k.topics[res.Name]
+				if res.Type != sarama.TopicResource || !ok {
+					continue
+				}
+				alterConfigs(topic.configs, res.ConfigEntries)
+				k.publish(res.Name)
+			}
+		case *sarama.OffsetCommitRequest:
+			if k.groups[req.ConsumerGroup] == nil {
+				k.groups[req.ConsumerGroup] = make(map[string]map[int32]int64)
+			}
+			for topic, partitions := range commitRequestOffsets(req) {
+				if k.groups[req.ConsumerGroup][topic] == nil {
+					k.groups[req.ConsumerGroup][topic] = make(map[int32]int64)
+				}
+				for p, o := range partitions {
+					k.groups[req.ConsumerGroup][topic][p] = o
+				}
+			}
+		}
+	}
+	k.applied = len(history)
+}
+
+func alterConfigs(configs map[string]string, entries map[string]sarama.IncrementalAlterConfigsEntry) {
+	for key, e := range entries {
+		if e.Operation == sarama.IncrementalAlterConfigsOperationDelete {
+			delete(configs, key)
+		} else {
+			configs[key] = *e.Value
+		}
+	}
+}
+
+// commitRequestOffsets returns the offsets of the request by topic and partition,
+// sarama does not export the blocks of an OffsetCommitRequest
+func commitRequestOffsets(req *sarama.OffsetCommitRequest) map[string]map[int32]int64 {
+	offsets := make(map[string]map[int32]int64)
+	blocks := reflect.ValueOf(req).Elem().FieldByName("blocks")
+	for _, topic := range blocks.MapKeys() {
+		offsets[topic.String()] = make(map[int32]int64)
+		partitions := blocks.MapIndex(topic)
+		for _, p := range partitions.MapKeys() {
+			offsets[topic.String()][int32(p.Int())] = partitions.MapIndex(p).Elem().FieldByName("offset").Int()
+		}
+	}
+	return offsets
+}
+
+// publish updates the REST view of the topic, k.mu is held
+func (k *fakeKafka) publish(topic string) {
+	if k.rest == nil {
+		return
+	}
+	k.rest.mu.Lock()
+	defer k.rest.mu.Unlock()
+
+	t, ok := k.topics[topic]
+	if !ok {
+		delete(k.rest.topics, fakeClusterId+"|"+topic)
+		return
+	}
+	// the configs changed over REST are kept
+	rest, ok := k.rest.topics[fakeClusterId+"|"+topic]
+	if !ok {
+		rest = &fakeTopic{configs: make(map[string]string)}
+		for key, v := range t.configs {
+			rest.configs[key] = v
+		}
+		k.rest.topics[fakeClusterId+"|"+topic] = rest
+	}
+	rest.partitions = len(t.replicas)
+	rest.replicationFactor = len(t.replicas[0])
+}
+
+// fakeSaramaClient is the sarama.Client of gonfluent and of the admin client, the other methods panic
+type fakeSaramaClient struct {
+	sarama.Client
+	k *fakeKafka
+}
+
+func (c fakeSaramaClient) Config() *sarama.Config {
+	return c.k.config
+}
+
+func (c fakeSaramaClient) Controller() (*sarama.Broker, error) {
+	return c.k.brokers[0], nil
+}
+
+func (c fakeSaramaClient) Broker(id int32) (*sarama.Broker, error) {
+	if id < 1 || int(id) > len(c.k.brokers) {
+		return nil, sarama.ErrBrokerNotFound
+	}
+	return c.k.brokers[0], nil
+}
+
+func (c fakeSaramaClient) Brokers() []*sarama.Broker {
+	return c.k.brokers
+}
+
+// ID is the broker ID for gonfluent, the brokers returned by sarama.NewBroker have no ID
+func (c fakeSaramaClient) ID(broker *sarama.Broker) int32 {
+	for i, b := range c.k.brokers {
+		if b == broker {
+			return int32(i + 1)
+		}
+	}
+	return -1
+}
+
+func (c fakeSaramaClient) RefreshMetadata(...string) error {
+	c.k.sync()
+	return nil
+}
+
+// fakeGonfluentClient is the confluent.SaramaClient of gonfluent, which refreshes the metadata of every topic
+type fakeGonfluentClient struct {
+	fakeSaramaClient
+}
+
+func (c fakeGonfluentClient) RefreshMetadata() error {
+	return c.fakeSaramaClient.RefreshMetadata()
+}
+
+func (c fakeSaramaClient) Partitions(topic string) ([]int32, error) {
+	c.k.sync()
+	c.k.mu.Lock()
+	defer c.k.mu.Unlock()
+
+	t, ok := c.k.topics[topic]
+	if !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	partitions := make([]int32, len(t.replicas))
+	for p := range t.replicas {
+		partitions[p] = int32(p)
+	}
+	return partitions, nil
+}
+
+// GetOffset returns the start or the end offset of the partition, there is no message at a timestamp
+func (c fakeSaramaClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	c.k.sync()
+	c.k.mu.Lock()
+	defer c.k.mu.Unlock()
+
+	t, ok := c.k.topics[topic]
+	if !ok || int(partition) >= len(t.replicas) {
+		return 0, sarama.ErrUnknownTopicOrPartition
+	}
+	switch time {
+	case sarama.OffsetOldest:
+		return 0, nil
+	case sarama.OffsetNewest:
+		return t.ends[partition], nil
+	default:
+		return -1, nil
+	}
+}
+
+// Coordinator is the mock broker, which accepts every OffsetCommit
+func (c fakeSaramaClient) Coordinator(string) (*sarama.Broker, error) {
+	return c.k.brokers[0], nil
+}
+
+func (c fakeSaramaClient) Replicas(topic string, partition int32) ([]int32, error) {
+	c.k.sync()
+	c.k.mu.Lock()
+	defer c.k.mu.Unlock()
+
+	t, ok := c.k.topics[topic]
+	if !ok || int(partition) >= len(t.replicas) {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	return append([]int32(nil), t.replicas[partition]...), nil
+}
+
+// fakeClusterAdmin is the sarama.ClusterAdmin of gonfluent and of the admin backend, the other methods panic
+type fakeClusterAdmin struct {
+	sarama.ClusterAdmin
+	k *fakeKafka
+}
+
+func (a fakeClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, _ bool) error {
+	a.k.mu.Lock()
+	_, ok := a.k.topics[topic]
+	a.k.mu.Unlock()
+	if ok {
+		return sarama.ErrTopicAlreadyExists
+	}
+
+	replicationFactor := int(detail.ReplicationFactor)
+	if replicationFactor < 0 {
+		replicationFactor = fakeBrokers
+	}
+	configs := make(map[string]string)
+	for key, v := range detail.ConfigEntries {
+		configs[key] = *v
+	}
+	a.k.createTopic(topic, int(detail.NumPartitions), replicationFactor, configs)
+	return nil
+}
+
+func (a fakeClusterAdmin) DeleteTopic(topic string) error {
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	if _, ok := a.k.topics[topic]; !ok {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	delete(a.k.topics, topic)
+	a.k.publish(topic)
+	return nil
+}
+
+func (a fakeClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
+	a.k.sync()
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	topics := make(map[string]sarama.TopicDetail)
+	for name, t := range a.k.topics {
+		topics[name] = sarama.TopicDetail{NumPartitions: int32(len(t.replicas)), ReplicationFactor: int16(len(t.replicas[0]))}
+	}
+	return topics, nil
+}
+
+func (a fakeClusterAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
+	a.k.sync()
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	var metadata []*sarama.TopicMetadata
+	for _, name := range topics {
+		t, ok := a.k.topics[name]
+		if !ok {
+			metadata = append(metadata, &sarama.TopicMetadata{Name: name, Err: sarama.ErrUnknownTopicOrPartition})
+			continue
+		}
+		m := &sarama.TopicMetadata{Name: name}
+		for p, replicas := range t.replicas {
+			m.Partitions = append(m.Partitions, &sarama.PartitionMetadata{
+				ID:       int32(p),
+				Leader:   replicas[0],
+				Replicas: replicas,
+				Isr:      replicas,
+			})
+		}
+		metadata = append(metadata, m)
+	}
+	return metadata, nil
+}
+
+func (a fakeClusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sarama.ConfigEntry, error) {
+	a.k.sync()
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	if resource.Type == sarama.BrokerResource {
+		return a.k.describeBroker(resource.Name), nil
+	}
+	t, ok := a.k.topics[resource.Name]
+	if resource.Type != sarama.TopicResource || !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	var entries []sarama.ConfigEntry
+	for key, v := range t.configs {
+		entries = append(entries, sarama.ConfigEntry{Name: key, Value: v, Source: sarama.SourceTopic})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// describeBroker returns the configs of a broker like Kafka: the config set for the broker hides
+// the cluster-wide default, the sensitive configs have no value. k.mu is held
+func (k *fakeKafka) describeBroker(brokerId string) []sarama.ConfigEntry {
+	entries := []sarama.ConfigEntry{{Name: "log.dirs", Value: "/var/lib/kafka", Source: sarama.SourceStaticBroker, ReadOnly: true}}
+	add := func(configs map[string]string, source sarama.ConfigSource) {
+		for key, v := range configs {
+			e := sarama.ConfigEntry{Name: key, Value: v, Source: source}
+			if strings.Contains(key, "password") {
+				e.Value, e.Sensitive = "", true
+			}
+			entries = append(entries, e)
+		}
+	}
+	if brokerId == "" {
+		add(k.brokerConfigs[""], sarama.SourceDynamicDefaultBroker)
+	} else {
+		add(k.brokerConfigs[brokerId], sarama.SourceDynamicBroker)
+		defaults := make(map[string]string)
+		for key, v := range k.brokerConfigs[""] {
+			if _, ok := k.brokerConfigs[brokerId][key]; !ok {
+				defaults[key] = v
+			}
+		}
+		add(defaults, sarama.SourceDynamicDefaultBroker)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+func (a fakeClusterAdmin) DescribeUserScramCredentials(users []string) ([]*sarama.DescribeUserScramCredentialsResult, error) {
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	var results []*sarama.DescribeUserScramCredentialsResult
+	for _, user := range users {
+		r := &sarama.DescribeUserScramCredentialsResult{User: user}
+		if len(a.k.scram[user]) == 0 {
+			r.ErrorCode = errResourceNotFound
+		}
+		for mechanism, credential := range a.k.scram[user] {
+			r.CredentialInfos = append(r.CredentialInfos, &sarama.UserScramCredentialsResponseInfo{Mechanism: mechanism, Iterations: credential.Iterations})
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func (a fakeClusterAdmin) UpsertUserScramCredentials(upsert []sarama.AlterUserScramCredentialsUpsert) ([]*sarama.AlterUserScramCredentialsResult, error) {
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	var results []*sarama.AlterUserScramCredentialsResult
+	for _, u := range upsert {
+		if a.k.scram[u.Name] == nil {
+			a.k.scram[u.Name] = make(map[sarama.ScramMechanismType]sarama.AlterUserScramCredentialsUpsert)
+		}
+		a.k.scram[u.Name][u.Mechanism] = u
+		results = append(results, &sarama.AlterUserScramCredentialsResult{User: u.Name})
+	}
+	return results, nil
+}
+
+func (a fakeClusterAdmin) DeleteUserScramCredentials(del []sarama.AlterUserScramCredentialsDelete) ([]*sarama.AlterUserScramCredentialsResult, error) {
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	var results []*sarama.AlterUserScramCredentialsResult
+	for _, d := range del {
+		r := &sarama.AlterUserScramCredentialsResult{User: d.Name}
+		if _, ok := a.k.scram[d.Name][d.Mechanism]; ok {
+			delete(a.k.scram[d.Name], d.Mechanism)
+		} else {
+			r.ErrorCode = errResourceNotFound
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// ListConsumerGroups lists the groups with committed offsets
+func (a fakeClusterAdmin) ListConsumerGroups() (map[string]string, error) {
+	a.k.sync()
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	groups := make(map[string]string)
+	for group := range a.k.groups {
+		groups[group] = "consumer"
+	}
+	return groups, nil
+}
+
+// DescribeConsumerGroups reports the groups with committed offsets as Empty, the other groups as Dead
+func (a fakeClusterAdmin) DescribeConsumerGroups(groups []string) ([]*sarama.GroupDescription, error) {
+	a.k.sync()
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	var descriptions []*sarama.GroupDescription
+	for _, group := range groups {
+		state := "Dead"
+		if _, ok := a.k.groups[group]; ok {
+			state = "Empty"
+		}
+		descriptions = append(descriptions, &sarama.GroupDescription{GroupId: group, State: state})
+	}
+	return descriptions, nil
+}
+
+func (a fakeClusterAdmin) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	a.k.sync()
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	rsp := &sarama.OffsetFetchResponse{}
+	for topic, partitions := range topicPartitions {
+		for _, p := range partitions {
+			o, ok := a.k.groups[group][topic][p]
+			if !ok {
+				o = -1
+			}
+			rsp.AddBlock(topic, p, &sarama.OffsetFetchResponseBlock{Offset: o})
+		}
+	}
+	return rsp, nil
+}
+
+func (k *fakeKafka) describeClientQuotas(_ context.Context, entity quotaEntity) (map[string]float64, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	values := make(map[string]float64)
+	for key, v := range k.quotas[quotaId(entity)] {
+		values[key] = v
+	}
+	return values, nil
+}
+
+func (k *fakeKafka) alterClientQuotas(_ context.Context, entity quotaEntity, set map[string]float64, remove []string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	id := quotaId(entity)
+	if k.quotas[id] == nil {
+		k.quotas[id] = make(map[string]float64)
+	}
+	for key, v := range set {
+		k.quotas[id][key] = v
+	}
+	for _, key := range remove {
+		delete(k.quotas[id], key)
+	}
+	return nil
+}
+
+func (a fakeClusterAdmin) AlterPartitionReassignments(topic string, assignment [][]int32) error {
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	if _, ok := a.k.topics[topic]; !ok {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	targets := make(map[int32][]int32)
+	for p, replicas := range assignment {
+		targets[int32(p)] = replicas
+	}
+	a.k.reassignments[topic] = targets
+	return nil
+}
+
+// ListPartitionReassignments reports the reassignments in progress, they complete after reassignPolls listings
+func (a fakeClusterAdmin) ListPartitionReassignments(topic string, partitions []int32) (map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus, error) {
+	a.k.mu.Lock()
+	defer a.k.mu.Unlock()
+
+	a.k.listings++
+	targets, ok := a.k.reassignments[topic]
+	if !ok {
+		return map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus{}, nil
+	}
+	t := a.k.topics[topic]
+	if a.k.reassignPolls <= 0 {
+		for p, replicas := range targets {
+			t.replicas[p] = replicas
+		}
+		delete(a.k.reassignments, topic)
+		a.k.publish(topic)
+		return map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus{}, nil
+	}
+	a.k.reassignPolls--
+
+	status := make(map[int32]*sarama.PartitionReplicaReassignmentsStatus)
+	for _, p := range partitions {
+		target, ok := targets[p]
+		if !ok {
+			continue
+		}
+		s := &sarama.PartitionReplicaReassignmentsStatus{Replicas: target}
+		s.AddingReplicas = difference(target, t.replicas[p])
+		s.RemovingReplicas = difference(t.replicas[p], target)
+		status[p] = s
+	}
+	return map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus{topic: status}, nil
+}
+
+func difference(a, b []int32) []int32 {
+	var r []int32
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			found = found || v == w
+		}
+		if !found {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// applyTopic plans and applies the kafka_topic from the attributes of the state to the new configuration,
+// like terraform apply but without terraform
+func applyTopic(t *testing.T, c *Client, state, config map[string]interface{}) (*terraform.InstanceState, diag.Diagnostics) {
+	ctx := context.Background()
+	r := topics()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, state)
+	d.SetId(buildId(state["cluster_id"].(string), state["name"].(string)))
+	diff, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r.Apply(ctx, d.State(), diff, c)
+}
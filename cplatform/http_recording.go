@@ -0,0 +1,210 @@
+package cplatform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	confluent "github.com/OneMount/gonfluent"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// errNoKafka is returned by the features using the Kafka protocol, which is not recorded,
+	// and which is not used with topic_api = "rest"
+	errNoKafka = errors.New("the Kafka protocol is not available while replaying the HTTP recordings or with topic_api = \"rest\"")
+
+	// sensitiveKey matches the JSON keys, and the names of the configs, holding secrets
+	sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|jaas|credential|private|(^|[._])key$)`)
+
+	// recordingMu serializes the writes of the recorders, every bootstrap server has its own
+	recordingMu sync.Mutex
+)
+
+// httpInteraction is a request to MDS or the REST API and its response, one JSON per line in the recording file
+type httpInteraction struct {
+	Method     string `json:"method"`
+	Uri        string `json:"uri"`
+	Request    string `json:"request,omitempty"`
+	StatusCode int    `json:"status_code"`
+	Status     string `json:"status"`
+	Response   string `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// recordingHttpClient appends every request and its response to a file, with the secrets redacted
+type recordingHttpClient struct {
+	next    confluent.HttpClient
+	file    string
+	secrets []string
+}
+
+func newRecordingHttpClient(next confluent.HttpClient, file string, secrets []string) confluent.HttpClient {
+	return &recordingHttpClient{next: next, file: file, secrets: secrets}
+}
+
+func (r *recordingHttpClient) DoRequest(method string, uri string, reqBody io.Reader) ([]byte, int, string, error) {
+	var request []byte
+	if reqBody != nil {
+		var err error
+		if request, err = ioutil.ReadAll(reqBody); err != nil {
+			return nil, 0, "", err
+		}
+		reqBody = bytes.NewReader(request)
+	}
+
+	response, statusCode, status, err := r.next.DoRequest(method, uri, reqBody)
+
+	i := httpInteraction{
+		Method:     method,
+		Uri:        uri,
+		Request:    redactBody(request, r.secrets),
+		StatusCode: statusCode,
+		Status:     status,
+		Response:   redactBody(response, r.secrets),
+	}
+	if err != nil {
+		i.Error = redactBody([]byte(err.Error()), r.secrets)
+	}
+	if werr := r.write(i); werr != nil {
+		return nil, 0, "", fmt.Errorf("cannot record the HTTP request to %s: %w", r.file, werr)
+	}
+
+	return response, statusCode, status, err
+}
+
+func (r *recordingHttpClient) write(i httpInteraction) error {
+	line, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	recordingMu.Lock()
+	defer recordingMu.Unlock()
+	f, err := os.OpenFile(r.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// replayHttpClient answers the requests with the responses of a recording file.
+// The responses of the same method and URI are returned in the recorded order, the last one is repeated
+// once they are all used, so a recording can be replayed by the successive runs of terraform.
+type replayHttpClient struct {
+	mu        sync.Mutex
+	responses map[string][]httpInteraction
+	next      map[string]int
+}
+
+func newReplayHttpClient(file string) (confluent.HttpClient, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := &replayHttpClient{
+		responses: make(map[string][]httpInteraction),
+		next:      make(map[string]int),
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var i httpInteraction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("invalid HTTP recording %s at line %d: %w", file, n, err)
+		}
+		key := i.Method + " " + i.Uri
+		r.responses[key] = append(r.responses[key], i)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *replayHttpClient) DoRequest(method string, uri string, _ io.Reader) ([]byte, int, string, error) {
+	key := method + " " + uri
+
+	r.mu.Lock()
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		r.mu.Unlock()
+		return nil, 0, "", fmt.Errorf("no recorded response for %s", key)
+	}
+	n := r.next[key]
+	if n < len(responses)-1 {
+		r.next[key] = n + 1
+	}
+	r.mu.Unlock()
+
+	i := responses[n]
+	if i.Error != "" {
+		return nil, 0, "", errors.New(i.Error)
+	}
+	return []byte(i.Response), i.StatusCode, i.Status, nil
+}
+
+// redactBody replaces the values of the sensitive keys of a JSON body, then every given secret
+func redactBody(body []byte, secrets []string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	s := string(body)
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if b, err := json.Marshal(redactJSON(v)); err == nil {
+			s = string(b)
+		}
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// redactJSON redacts the values of the sensitive keys and the value of the configs with a sensitive name,
+// like {"name": "sasl.jaas.config", "value": "..."} of the cluster links
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if name, ok := v["name"].(string); ok && sensitiveKey.MatchString(name) {
+			if _, ok := v["value"]; ok {
+				v["value"] = redacted
+			}
+		}
+		for k, e := range v {
+			if _, ok := e.(string); ok && sensitiveKey.MatchString(k) {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactJSON(e)
+		}
+	case []interface{}:
+		for k, e := range v {
+			v[k] = redactJSON(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package cplatform
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+)
+
+func TestHttpRecording_recordAndReplay(t *testing.T) {
+	f := newFakeConfluent(t)
+	file := filepath.Join(t.TempDir(), "confluent.jsonl")
+
+	httpClient := confluent.NewDefaultHttpClient(f.URL, fakeUsername, fakePassword)
+	recorder := confluent.NewClient(newRecordingHttpClient(httpClient, file, []string{fakePassword}), nil, nil)
+	token, err := recorder.Login()
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient.Token = token
+
+	jaas := `org.apache.kafka.common.security.plain.PlainLoginModule required username="link" password="link-secret";`
+	if err := createClusterLink(recorder, fakeClusterId, "dr", "source-cluster", map[string]string{"sasl.jaas.config": jaas}); err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := getClusterLink(recorder, fakeClusterId, "dr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.GetTopic(fakeClusterId, "missing"); err == nil {
+		t.Fatal("expected the missing topic to fail")
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{fakePassword, fakeToken, "link-secret"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("the recording contains the secret %s:\n%s", secret, b)
+		}
+	}
+
+	replay, err := newReplayHttpClient(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := confluent.NewClient(replay, nil, nil)
+	if token, err := c.Login(); err != nil || token != redacted {
+		t.Errorf("replayed token %q, %v", token, err)
+	}
+	replayed, err := getClusterLink(c, fakeClusterId, "dr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("replayed link %+v, want %+v", replayed, recorded)
+	}
+	if _, err := c.GetTopic(fakeClusterId, "missing"); err == nil || !isTopicNotFound(err) {
+		t.Errorf("replayed missing topic: %v", err)
+	}
+	if _, err := c.ListKafkaCluster(); err == nil {
+		t.Error("expected a request without recording to fail")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	cases := map[string]string{
+		`{"auth_token":"abc","token_type":"Bearer","expires_in":3600}`:                                 `{"auth_token":"[REDACTED]","expires_in":3600,"token_type":"[REDACTED]"}`,
+		`{"data":[{"name":"sasl.jaas.config","value":"x"},{"name":"acl.sync.enable","value":"true"}]}`: `{"data":[{"name":"sasl.jaas.config","value":"[REDACTED]"},{"name":"acl.sync.enable","value":"true"}]}`,
+		`{"principal":"User:alice","password":"hunter2"}`:                                              `{"password":"[REDACTED]","principal":"User:alice"}`,
+		`not json with hunter2`: `not json with [REDACTED]`,
+	}
+	for body, want := range cases {
+		if got := redactBody([]byte(body), []string{"hunter2"}); got != want {
+			t.Errorf("redactBody(%s) = %s, want %s", body, got, want)
+		}
+	}
+}
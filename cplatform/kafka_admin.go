@@ -0,0 +1,97 @@
+package cplatform
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+)
+
+// kafkaAdmin returns the Kafka admin client for the admin APIs that gonfluent does not provide,
+// it only connects on first use.
+// gonfluent configures its client for Kafka 2.4 while the SCRAM credentials APIs need Kafka 2.7,
+// so the admin client has its own copy of the config with the kafka_version of the provider.
+func (c *Client) kafkaAdmin() (sarama.ClusterAdmin, error) {
+	c.adminOnce.Do(func() {
+		if c.kafka == nil {
+			c.adminErr = errNoKafka
+			return
+		}
+		cfg := *c.kafka.Config()
+		cfg.Version = c.kafkaVersion
+
+		kafka, err := sarama.NewClient(c.bootstrapServers, &cfg)
+		if err != nil {
+			c.adminErr = err
+			return
+		}
+		c.adminKafka = kafka
+		c.admin, c.adminErr = sarama.NewClusterAdminFromClient(kafka)
+	})
+	return c.admin, c.adminErr
+}
+
+// incrementalAlterConfigs only changes the given configs of the resource, the other dynamic configs are kept.
+// The ClusterAdmin of sarama v1.29 only has AlterConfig which replaces every dynamic config of the resource.
+func (c *Client) incrementalAlterConfigs(resource sarama.ConfigResource, entries map[string]sarama.IncrementalAlterConfigsEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if _, err := c.kafkaAdmin(); err != nil {
+		return err
+	}
+
+	// The configs of a broker must be sent to the broker in question
+	var (
+		b   *sarama.Broker
+		err error
+	)
+	if resource.Type == sarama.BrokerResource && resource.Name != "" {
+		var id int64
+		id, err = strconv.ParseInt(resource.Name, 10, 32)
+		if err != nil {
+			return err
+		}
+		b, err = c.adminKafka.Broker(int32(id))
+	} else {
+		b, err = c.adminKafka.Controller()
+	}
+	if err != nil {
+		return err
+	}
+
+	// The brokers other than the controller may not be connected yet
+	if err := b.Open(c.adminKafka.Config()); err != nil && !errors.Is(err, sarama.ErrAlreadyConnected) {
+		return err
+	}
+	rsp, err := b.IncrementalAlterConfigs(&sarama.IncrementalAlterConfigsRequest{
+		Resources: []*sarama.IncrementalAlterConfigsResource{
+			{
+				Type:          resource.Type,
+				Name:          resource.Name,
+				ConfigEntries: entries,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range rsp.Resources {
+		if err := kafkaError(sarama.KError(r.ErrorCode), &r.ErrorMsg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// kafkaError returns nil when there is no error, adding the message sent by Kafka otherwise
+func kafkaError(err sarama.KError, message *string) error {
+	if err == sarama.ErrNoError {
+		return nil
+	}
+	if message != nil && *message != "" {
+		return fmt.Errorf("%w: %s", err, *message)
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package cplatform
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+)
+
+// describeConsumerGroup returns the state and the members of a group, a group which does not exist is Dead
+func (c *Client) describeConsumerGroup(group string) (*sarama.GroupDescription, error) {
+	admin, err := c.kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := admin.DescribeConsumerGroups([]string{group})
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if g.GroupId != group {
+			continue
+		}
+		if err := kafkaError(g.Err, nil); err != nil {
+			return nil, err
+		}
+		return g, nil
+	}
+	return nil, fmt.Errorf("consumer group %s is not returned by Kafka", group)
+}
+
+// committedOffsets returns the offsets committed by a group by topic and partition, the partitions
+// without any committed offset are not returned. Every topic is returned when topicPartitions is nil
+func (c *Client) committedOffsets(group string, topicPartitions map[string][]int32) (map[string]map[int32]int64, error) {
+	admin, err := c.kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := admin.ListConsumerGroupOffsets(group, topicPartitions)
+	if err != nil {
+		return nil, err
+	}
+	if err := kafkaError(rsp.Err, nil); err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[string]map[int32]int64)
+	for topic, partitions := range rsp.Blocks {
+		for p, block := range partitions {
+			if err := kafkaError(block.Err, nil); err != nil {
+				return nil, fmt.Errorf("%s partition %d: %w", topic, p, err)
+			}
+			if block.Offset < 0 {
+				continue
+			}
+			if offsets[topic] == nil {
+				offsets[topic] = make(map[int32]int64)
+			}
+			offsets[topic][p] = block.Offset
+		}
+	}
+	return offsets, nil
+}
+
+// commitOffsets commits the offsets of a group without joining it, like kafka-consumer-groups --reset-offsets.
+// Kafka rejects the commit while the group has members.
+func (c *Client) commitOffsets(group string, topic string, offsets map[int32]int64) error {
+	if _, err := c.kafkaAdmin(); err != nil {
+		return err
+	}
+
+	coordinator, err := c.adminKafka.Coordinator(group)
+	if err != nil {
+		return err
+	}
+
+	request := &sarama.OffsetCommitRequest{
+		ConsumerGroup:           group,
+		ConsumerGroupGeneration: sarama.GroupGenerationUndefined,
+		RetentionTime:           -1,
+		Version:                 2,
+	}
+	for p, o := range offsets {
+		request.AddBlock(topic, p, o, 0, "")
+	}
+
+	rsp, err := coordinator.CommitOffset(request)
+	if err != nil {
+		return err
+	}
+	for _, partitions := range rsp.Errors {
+		for p, kerr := range partitions {
+			if err := kafkaError(kerr, nil); err != nil {
+				return fmt.Errorf("partition %d: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
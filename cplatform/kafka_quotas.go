@@ -0,0 +1,149 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// The ClusterAdmin of sarama only has the client quotas APIs (KIP-546) from v1.30, which gonfluent v0.1.1 does not
+// build with since it uses the ApiVersionsResponseBlock type removed by that version. Until gonfluent is upgraded the
+// quotas are managed with a franz-go client built from the same Kafka config.
+// gonfluent validates sasl_mechanism but never sets it in the sarama config, so the mechanism comes from the provider.
+
+// quotaEntity is a single quota entity, Name is nil for the default entity
+type quotaEntity struct {
+	Type string
+	Name *string
+}
+
+// quotaClient returns the client used for the quotas, it only connects on first use
+func (c *Client) quotaClient() (*kgo.Client, error) {
+	c.quotasOnce.Do(func() {
+		if c.kafka == nil {
+			c.quotasErr = errNoKafka
+			return
+		}
+		cfg := c.kafka.Config()
+		opts := []kgo.Opt{
+			kgo.SeedBrokers(c.bootstrapServers...),
+			kgo.ClientID(cfg.ClientID),
+		}
+		if cfg.Net.TLS.Enable {
+			opts = append(opts, kgo.DialTLSConfig(cfg.Net.TLS.Config.Clone()))
+		}
+		if cfg.Net.SASL.Enable {
+			switch c.saslMechanism {
+			case "scram-sha256":
+				opts = append(opts, kgo.SASL(scram.Auth{User: cfg.Net.SASL.User, Pass: cfg.Net.SASL.Password}.AsSha256Mechanism()))
+			case "scram-sha512":
+				opts = append(opts, kgo.SASL(scram.Auth{User: cfg.Net.SASL.User, Pass: cfg.Net.SASL.Password}.AsSha512Mechanism()))
+			default:
+				opts = append(opts, kgo.SASL(plain.Auth{User: cfg.Net.SASL.User, Pass: cfg.Net.SASL.Password}.AsMechanism()))
+			}
+		}
+		c.quotas, c.quotasErr = kgo.NewClient(opts...)
+	})
+	return c.quotas, c.quotasErr
+}
+
+// kgoQuotaAdmin is the clientQuotaAdmin of the meta, sending the requests with the client of quotaClient
+type kgoQuotaAdmin struct {
+	c *Client
+}
+
+// describeClientQuotas returns the quotas of exactly this entity, an empty map when it has none
+func (a kgoQuotaAdmin) describeClientQuotas(ctx context.Context, entity quotaEntity) (map[string]float64, error) {
+	cl, err := a.c.quotaClient()
+	if err != nil {
+		return nil, err
+	}
+
+	component := kmsg.NewDescribeClientQuotasRequestComponent()
+	component.EntityType = entity.Type
+	if entity.Name == nil {
+		component.MatchType = 1
+	} else {
+		component.Match = entity.Name
+	}
+	req := kmsg.NewPtrDescribeClientQuotasRequest()
+	req.Components = append(req.Components, component)
+	req.Strict = true
+
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	if err := quotaError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64)
+	for _, e := range resp.Entries {
+		for _, v := range e.Values {
+			values[v.Key] = v.Value
+		}
+	}
+	return values, nil
+}
+
+// alterClientQuotas sets the given quotas and removes the quotas listed in remove
+func (a kgoQuotaAdmin) alterClientQuotas(ctx context.Context, entity quotaEntity, set map[string]float64, remove []string) error {
+	cl, err := a.c.quotaClient()
+	if err != nil {
+		return err
+	}
+
+	e := kmsg.NewAlterClientQuotasRequestEntryEntity()
+	e.Type = entity.Type
+	e.Name = entity.Name
+
+	entry := kmsg.NewAlterClientQuotasRequestEntry()
+	entry.Entity = append(entry.Entity, e)
+	for k, v := range set {
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key = k
+		op.Value = v
+		entry.Ops = append(entry.Ops, op)
+	}
+	for _, k := range remove {
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key = k
+		op.Remove = true
+		entry.Ops = append(entry.Ops, op)
+	}
+	if len(entry.Ops) == 0 {
+		return nil
+	}
+
+	req := kmsg.NewPtrAlterClientQuotasRequest()
+	req.Entries = append(req.Entries, entry)
+
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return err
+	}
+	for _, v := range resp.Entries {
+		if err := quotaError(v.ErrorCode, v.ErrorMessage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func quotaError(code int16, message *string) error {
+	err := kerr.ErrorForCode(code)
+	if err == nil {
+		return nil
+	}
+	if message != nil && strings.TrimSpace(*message) != "" {
+		return fmt.Errorf("%s: %s", err, *message)
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package cplatform
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The subsystems of the logs, their level can be set with TF_LOG_PROVIDER_CONFLUENT_KAFKA,
+// TF_LOG_PROVIDER_CONFLUENT_MDS and TF_LOG_PROVIDER_CONFLUENT_SCHEMA_REGISTRY
+const (
+	logKafka          = "kafka"
+	logMDS            = "mds"
+	logSchemaRegistry = "schema-registry"
+)
+
+var (
+	logSubsystems = []string{logKafka, logMDS, logSchemaRegistry}
+
+	// sensitiveLogFields are masked in every log, whatever their value
+	sensitiveLogFields = []string{
+		"password",
+		"sasl_password",
+		"source_password",
+		"token",
+		"auth_token",
+		"client_key",
+		"client_key_passphrase",
+		"sasl.jaas.config",
+	}
+
+	// sensitiveLogValues are masked in the messages and the fields, like the password of a JAAS config in an error
+	sensitiveLogValues = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)password="[^"]*"`),
+		regexp.MustCompile(`(?i)bearer [A-Za-z0-9._~+/=-]+`),
+	}
+)
+
+// withLogging sets up tflog for the functions of the resources and the data sources.
+// The SDK of the provider does not create the tflog loggers, so every function gets a new root logger.
+func withLogging(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for _, r := range resources {
+		if f := r.CreateContext; f != nil {
+			r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				return f(logContext(ctx, meta), d, meta)
+			}
+		}
+		if f := r.ReadContext; f != nil {
+			r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				return f(logContext(ctx, meta), d, meta)
+			}
+		}
+		if f := r.UpdateContext; f != nil {
+			r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				return f(logContext(ctx, meta), d, meta)
+			}
+		}
+		if f := r.DeleteContext; f != nil {
+			r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				return f(logContext(ctx, meta), d, meta)
+			}
+		}
+		if f := r.CustomizeDiff; f != nil {
+			r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+				return f(logContext(ctx, meta), d, meta)
+			}
+		}
+		if r.Importer != nil && r.Importer.StateContext != nil {
+			f := r.Importer.StateContext
+			r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				return f(logContext(ctx, meta), d, meta)
+			}
+		}
+	}
+	return resources
+}
+
+// logContext returns ctx with the root logger of the provider and its subsystems
+func logContext(ctx context.Context, meta interface{}) context.Context {
+	ctx = tfsdklog.NewRootProviderLogger(ctx, tfsdklog.WithLogName("confluent"), tfsdklog.WithLevelFromEnv("TF_LOG_PROVIDER"))
+	return withLoggers(ctx, meta)
+}
+
+// withLoggers adds the subsystems to the root logger of ctx, every logger masks the secrets of the provider
+func withLoggers(ctx context.Context, meta interface{}) context.Context {
+	var secrets []string
+	if c, ok := meta.(*Client); ok {
+		for _, s := range c.secrets {
+			if s != "" {
+				secrets = append(secrets, s)
+			}
+		}
+	}
+
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, sensitiveLogFields...)
+	ctx = tflog.MaskLogRegexes(ctx, sensitiveLogValues...)
+	ctx = tflog.MaskLogStrings(ctx, secrets...)
+	for _, s := range logSubsystems {
+		env := "TF_LOG_PROVIDER_CONFLUENT_" + strings.ToUpper(strings.ReplaceAll(s, "-", "_"))
+		ctx = tflog.NewSubsystem(ctx, s, tflog.WithLevelFromEnv(env))
+		ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, s, sensitiveLogFields...)
+		ctx = tflog.SubsystemMaskLogRegexes(ctx, s, sensitiveLogValues...)
+		ctx = tflog.SubsystemMaskLogStrings(ctx, s, secrets...)
+	}
+	return ctx
+}
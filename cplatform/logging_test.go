@@ -0,0 +1,45 @@
+package cplatform
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLogging_redaction(t *testing.T) {
+	var out bytes.Buffer
+	ctx := withLoggers(tflogtest.RootLogger(context.Background(), &out), &Client{secrets: []string{"admin-secret", ""}})
+
+	tflog.Info(ctx, "login with admin-secret", map[string]interface{}{"password": "hunter2"})
+	for _, s := range logSubsystems {
+		tflog.SubsystemError(ctx, s, "Cannot create the cluster link", map[string]interface{}{
+			"cluster_id": fakeClusterId,
+			"error":      `invalid sasl.jaas.config: ScramLoginModule required username="link" password="link-secret";`,
+			"token":      "abc",
+		})
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1+len(logSubsystems) {
+		t.Fatalf("got %d logs, want %d:\n%s", len(entries), 1+len(logSubsystems), out.String())
+	}
+	for _, e := range entries {
+		for k, v := range e {
+			for _, secret := range []string{"admin-secret", "hunter2", "link-secret", "abc"} {
+				if s, ok := v.(string); ok && strings.Contains(s, secret) {
+					t.Errorf("%s of %v contains the secret %s", k, e, secret)
+				}
+			}
+		}
+		if e["@module"] != "provider" && e["cluster_id"] != fakeClusterId {
+			t.Errorf("missing cluster_id in %v", e)
+		}
+	}
+}
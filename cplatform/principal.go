@@ -0,0 +1,139 @@
+package cplatform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// principalTypes are the types of the principals of the role bindings, by their lower case
+var principalTypes = map[string]string{
+	"user":  "User",
+	"group": "Group",
+}
+
+// principal is a principal of MDS, <type>:<name>
+type principal struct {
+	Type string
+	Name string
+}
+
+func (p principal) String() string {
+	return p.Type + ":" + p.Name
+}
+
+// parsePrincipal parses User:<name> and Group:<name>, the type is case insensitive.
+// A name which is an LDAP DN, like User:CN=alice,OU=eng,DC=example,DC=com, is normalized:
+// the attribute types are upper case and the spaces around "=" and "," are removed.
+func parsePrincipal(s string) (principal, error) {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return principal{}, fmt.Errorf("principal %q must be User:<name> or Group:<name>", s)
+	}
+	t, ok := principalTypes[strings.ToLower(strings.TrimSpace(s[:i]))]
+	if !ok {
+		return principal{}, fmt.Errorf("principal %q has the unknown type %q, expected User or Group", s, s[:i])
+	}
+	name := strings.TrimSpace(s[i+1:])
+	if name == "" {
+		return principal{}, fmt.Errorf("principal %q has no name", s)
+	}
+
+	if isDistinguishedName(name) {
+		dn, err := normalizeDistinguishedName(name)
+		if err != nil {
+			return principal{}, fmt.Errorf("principal %q: %w", s, err)
+		}
+		name = dn
+	}
+	return principal{Type: t, Name: name}, nil
+}
+
+// normalizePrincipal returns the normalized principal, or s when it cannot be parsed
+func normalizePrincipal(s string) string {
+	p, err := parsePrincipal(s)
+	if err != nil {
+		return s
+	}
+	return p.String()
+}
+
+// isDistinguishedName tells if the name of a principal is an LDAP DN, its first RDN is <attribute type>=<value>
+func isDistinguishedName(name string) bool {
+	i := strings.Index(name, "=")
+	if i <= 0 {
+		return false
+	}
+	attr := strings.TrimSpace(name[:i])
+	for _, r := range attr {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return attr != ""
+}
+
+// normalizeDistinguishedName upper cases the attribute types of the RDNs and removes the spaces around them,
+// the values are kept as they are, with their escaped characters like "\,"
+func normalizeDistinguishedName(dn string) (string, error) {
+	var rdns []string
+	for _, rdn := range splitUnescaped(dn, ',') {
+		var avas []string
+		for _, ava := range splitUnescaped(rdn, '+') {
+			i := strings.Index(ava, "=")
+			if i <= 0 {
+				return "", fmt.Errorf("invalid LDAP DN %q, %q is not <attribute type>=<value>", dn, strings.TrimSpace(ava))
+			}
+			attr := strings.ToUpper(strings.TrimSpace(ava[:i]))
+			value := strings.TrimSpace(ava[i+1:])
+			if attr == "" || value == "" {
+				return "", fmt.Errorf("invalid LDAP DN %q, %q is not <attribute type>=<value>", dn, strings.TrimSpace(ava))
+			}
+			avas = append(avas, attr+"="+value)
+		}
+		rdns = append(rdns, strings.Join(avas, "+"))
+	}
+	return strings.Join(rdns, ","), nil
+}
+
+// splitUnescaped splits s around the separators which are not escaped with "\"
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// validatePrincipal is the ValidateFunc of the principals
+func validatePrincipal(val interface{}, key string) (warns []string, errs []error) {
+	if _, err := parsePrincipal(val.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q: %w", key, err))
+	}
+	return
+}
+
+// suppressEquivalentPrincipal is the DiffSuppressFunc of the principals, user:cn=alice, ou=eng is User:CN=alice,OU=eng
+func suppressEquivalentPrincipal(k, old, new string, d *schema.ResourceData) bool {
+	return old != "" && normalizePrincipal(old) == normalizePrincipal(new)
+}
+
+// principalSchema is the principal of the resources binding a role
+func principalSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		ForceNew:         true,
+		Required:         true,
+		Description:      "The principal, User:<name> or Group:<name>, the name can be an LDAP DN like User:CN=alice,OU=eng,DC=example,DC=com",
+		ValidateFunc:     validatePrincipal,
+		DiffSuppressFunc: suppressEquivalentPrincipal,
+	}
+}
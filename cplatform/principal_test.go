@@ -0,0 +1,45 @@
+package cplatform
+
+import "testing"
+
+func TestParsePrincipal(t *testing.T) {
+	valid := map[string]string{
+		"User:alice":                             "User:alice",
+		"Group:platform-admins":                  "Group:platform-admins",
+		"user:alice":                             "User:alice",
+		"GROUP:ops":                              "Group:ops",
+		"User:CN=alice,OU=eng,DC=example,DC=com": "User:CN=alice,OU=eng,DC=example,DC=com",
+		"User:cn=alice, ou=eng , dc=example":     "User:CN=alice,OU=eng,DC=example",
+		`User:CN=Doe\, John,OU=eng`:              `User:CN=Doe\, John,OU=eng`,
+		"User:CN=alice+UID=42,OU=eng":            "User:CN=alice+UID=42,OU=eng",
+		"User:alice|ops":                         "User:alice|ops",
+	}
+	for s, want := range valid {
+		p, err := parsePrincipal(s)
+		if err != nil {
+			t.Errorf("parsePrincipal(%s): %v", s, err)
+			continue
+		}
+		if p.String() != want {
+			t.Errorf("parsePrincipal(%s) = %s, want %s", s, p, want)
+		}
+	}
+
+	for _, s := range []string{"alice", "User:", "Service:alice", ":alice", "User:CN=alice,,OU=eng", "User:CN=alice,OU="} {
+		if p, err := parsePrincipal(s); err == nil {
+			t.Errorf("parsePrincipal(%s) = %s, expected an error", s, p)
+		}
+	}
+}
+
+func TestSuppressEquivalentPrincipal(t *testing.T) {
+	if !suppressEquivalentPrincipal("principal", "User:CN=alice,OU=eng", "user:cn=alice, ou=eng", nil) {
+		t.Error("expected the spellings of the same principal not to be a diff")
+	}
+	if suppressEquivalentPrincipal("principal", "User:alice", "Group:alice", nil) {
+		t.Error("expected a user and a group to be a diff")
+	}
+	if suppressEquivalentPrincipal("principal", "", "User:alice", nil) {
+		t.Error("expected a new principal to be a diff")
+	}
+}
@@ -3,12 +3,18 @@ package cplatform
 import (
 	"context"
 	"fmt"
-	"log"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/twmb/franz-go/pkg/kgo"
 )
 
 var (
@@ -20,8 +26,48 @@ const (
 	UserAgent     = "confluent-client-go-sdk-" + ClientVersion
 )
 
+// Client is the meta of the provider.
+// gonfluent talks to Confluent REST/MDS but does not expose its Kafka clients,
+// so the Kafka features it does not provide are built on the clients kept here.
+// The resources go through the backends below, see client_api.go.
+type Client struct {
+	rest         restAPI
+	roleBindings roleBindingAdmin
+	clusters     clusterRegistry
+
+	kafka            sarama.Client
+	bootstrapServers []string
+	saslMechanism    string
+	protectTopics    []*regexp.Regexp
+	// kafkaVersion is the version of the Kafka clients opened besides the one of gonfluent, see kafka_admin.go
+	kafkaVersion sarama.KafkaVersion
+	// copyIdleTimeout ends the copy of a partition of a renamed topic, see topic_copy.go
+	copyIdleTimeout time.Duration
+	// secrets are masked in the logs, see logging.go
+	secrets []string
+	// topics is the backend of kafka_topic, see topic_api.go
+	topics topicAPI
+	// clientQuotas is the backend of kafka_quota, see kafka_quotas.go
+	clientQuotas clientQuotaAdmin
+	// roles are refreshed from MDS with refresh_roles, see role_matrix.go
+	roles map[string]roleScope
+	// defaultKafkaClusterId and clusterAliases resolve the cluster IDs of the resources, see cluster_alias.go
+	defaultKafkaClusterId string
+	clusterAliases        map[string]map[string]string
+
+	// admin is created on first use, see kafka_admin.go
+	admin      sarama.ClusterAdmin
+	adminKafka sarama.Client
+	adminErr   error
+	adminOnce  sync.Once
+
+	// quotas is created on first use, see kafka_quotas.go
+	quotas     *kgo.Client
+	quotasErr  error
+	quotasOnce sync.Once
+}
+
 func Provider() *schema.Provider {
-	log.Printf("[INFO] Creating Provider")
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"username": {
@@ -122,24 +168,125 @@ func Provider() *schema.Provider {
 				Default:     120,
 				Description: "Timeout in seconds",
 			},
+			"kafka_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "2.7.0",
+				Description: "The version of Kafka of the admin client and of the copy of the renamed topics, at least 2.7.0 for the SCRAM credentials",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if _, err := sarama.ParseKafkaVersion(val.(string)); err != nil {
+						errs = append(errs, fmt.Errorf("%q: %w", key, err))
+					}
+					return
+				},
+			},
+			"copy_idle_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				Description:  "Seconds without message after which the copy of a partition of a renamed topic checks that only transaction markers are left",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"topic_api": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "rest to manage the topics only with the REST API, admin only with the Kafka protocol. When not set the topics and their configs use the REST API, the partitions and the replicas the Kafka protocol",
+				ValidateFunc: validation.StringInSlice([]string{"rest", "admin"}, false),
+			},
+			"http_recording_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CONFLUENT_HTTP_RECORDING_MODE", nil),
+				Description:  "record to append the requests to MDS and the REST API and their responses to http_recording_file, with the secrets redacted. replay to answer the requests with the responses of http_recording_file instead of Confluent Server, the Kafka protocol is not used",
+				ValidateFunc: validation.StringInSlice([]string{"record", "replay"}, false),
+			},
+			"http_recording_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CONFLUENT_HTTP_RECORDING_FILE", nil),
+				Description: "The file of the HTTP recordings, one JSON per request",
+			},
+			"refresh_roles": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CONFLUENT_REFRESH_ROLES", false),
+				Description: "Read the roles of MDS to validate the role bindings, instead of the predefined roles of Confluent Platform",
+			},
+			"default_kafka_cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CONFLUENT_DEFAULT_KAFKA_CLUSTER_ID", nil),
+				Description: "The cluster_id of the resources which do not set it, the ID of a Kafka cluster or an alias of clusters",
+			},
+			"clusters": clustersSchema(),
+			"protect_topics_matching": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Regular expressions of the topic names which cannot be deleted or replaced",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+			},
 		},
 		ConfigureContextFunc: providerConfigure,
-		ResourcesMap: map[string]*schema.Resource{
-			"kafka_topic":          topics(),
-			"cluster_role_binding": clusterRoleBindings(),
-			"kafka_topic_rbac":     kafkaTopicRBAC(),
-			"schema_registry_rbac": schemaRegistryRBAC(),
-			"connectors_rbac":      connectorsRBAC(),
-		},
+		ResourcesMap: withLogging(map[string]*schema.Resource{
+			"kafka_topic":                  topics(),
+			"cluster_role_binding":         clusterRoleBindings(),
+			"kafka_topic_rbac":             kafkaTopicRBAC(),
+			"schema_registry_rbac":         schemaRegistryRBAC(),
+			"connectors_rbac":              connectorsRBAC(),
+			"kafka_quota":                  kafkaQuota(),
+			"kafka_scram_credential":       kafkaScramCredential(),
+			"kafka_broker_config":          kafkaBrokerConfig(),
+			"kafka_consumer_group_offsets": kafkaConsumerGroupOffsets(),
+			"kafka_cluster_link":           kafkaClusterLink(),
+			"kafka_mirror_topic":           kafkaMirrorTopic(),
+		}),
+		DataSourcesMap: withLogging(map[string]*schema.Resource{
+			"kafka_topic":              dataSourceTopic(),
+			"kafka_topics":             dataSourceTopics(),
+			"confluent_cluster":        dataSourceCluster(),
+			"rbac_principal_bindings":  dataSourceRBACPrincipalBindings(),
+			"rbac_resource_principals": dataSourceRBACResourcePrincipals(),
+			"kafka_consumer_group":     dataSourceConsumerGroup(),
+		}),
 	}
 }
-func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	log.Printf("[INFO] Initializing Confluent Platform client")
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
 	brokers := dTos("bootstrap_servers", d)
+	secrets := []string{password, d.Get("sasl_password").(string), d.Get("client_key").(string), d.Get("client_key_passphrase").(string)}
+	ctx = logContext(ctx, &Client{secrets: secrets})
+	tflog.Info(ctx, "Initializing the Confluent Platform client", map[string]interface{}{"username": username})
 
 	var diags diag.Diagnostics
+	var protectTopics []*regexp.Regexp
+	for _, v := range d.Get("protect_topics_matching").([]interface{}) {
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		protectTopics = append(protectTopics, re)
+	}
+
+	clusterAliases, err := clusterAliasesOf(d.Get("clusters").([]interface{}))
+	if err != nil {
+		return nil, errorDiags("Invalid clusters", err, "clusters")
+	}
+
+	recordingMode := d.Get("http_recording_mode").(string)
+	recordingFile := d.Get("http_recording_file").(string)
+	if recordingMode != "" && recordingFile == "" {
+		return nil, diag.FromErr(fmt.Errorf("http_recording_file is required with http_recording_mode = %q", recordingMode))
+	}
+	topicApi := d.Get("topic_api").(string)
+	kafkaVersion, err := sarama.ParseKafkaVersion(d.Get("kafka_version").(string))
+	if err != nil {
+		return nil, errorDiags("Invalid kafka_version", err, "kafka_version")
+	}
+
 	kConfig := &confluent.Config{
 		BootstrapServers: brokers,
 		CACert:           d.Get("ca_cert").(string),
@@ -150,29 +297,80 @@ func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{},
 		TLSEnabled:       d.Get("tls_enabled").(bool),
 		Timeout:          d.Get("timeout").(int),
 	}
-	kClient, sarama, err := confluent.NewDefaultSaramaClient(kConfig)
-	if err != nil {
-		return nil, diag.FromErr(err)
-	}
+	var (
+		kClient     confluent.SaramaClient
+		kafka       sarama.Client
+		saramaAdmin confluent.SaramaClusterAdmin
+		replay      confluent.HttpClient
+	)
+	switch {
+	case recordingMode == "replay":
+		// only the requests to MDS and the REST API are recorded, the topics are managed with the REST API
+		tflog.Warn(ctx, "Replaying the HTTP recordings, the Kafka protocol is not used", map[string]interface{}{"file": recordingFile})
+		var err error
+		if replay, err = newReplayHttpClient(recordingFile); err != nil {
+			return nil, diag.FromErr(err)
+		}
+		topicApi = "rest"
+	case topicApi == "rest":
+		// only the HTTPS port of Confluent Server may be reachable, the brokers are not dialed and
+		// the resources using the Kafka protocol fail with errNoKafka
+		tflog.Info(ctx, "Managing the topics with the REST API only, the Kafka protocol is not used")
+	default:
+		dClient, dKafka, err := confluent.NewDefaultSaramaClient(kConfig)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		kClient, kafka = dClient, dKafka
 
-	saramaAdmin, err := confluent.NewDefaultSaramaClusterAdmin(sarama)
-	if err != nil {
-		return nil, diag.FromErr(err)
+		saramaAdmin, err = confluent.NewDefaultSaramaClusterAdmin(kafka)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
 	}
 
 	for _, v := range d.Get("bootstrap_servers").([]interface{}) {
 		baseUrl := "https://" + strings.Replace(v.(string), "9093", "8090", 1)
 		httpClient := confluent.NewDefaultHttpClient(baseUrl, username, password)
-		client := confluent.NewClient(httpClient, kClient, saramaAdmin)
+		var transport confluent.HttpClient = httpClient
+		switch recordingMode {
+		case "record":
+			transport = newRecordingHttpClient(httpClient, recordingFile, secrets)
+		case "replay":
+			transport = replay
+		}
+		client := confluent.NewClient(transport, kClient, saramaAdmin)
 		httpClient.UserAgent = UserAgent
 		bearerToken, err := client.Login()
 		if err == nil {
 			httpClient.Token = bearerToken
-			return client, diags
-		}
+			c := &Client{
+				kafka:            kafka,
+				bootstrapServers: *brokers,
+				saslMechanism:    kConfig.SASLMechanism,
+				protectTopics:    protectTopics,
+				kafkaVersion:     kafkaVersion,
+				copyIdleTimeout:  time.Duration(d.Get("copy_idle_timeout").(int)) * time.Second,
+				secrets:          append(secrets, bearerToken),
 
-		log.Printf("[WARN] Cannot login to confluent server " + baseUrl + ". Retry with another server!.")
+				defaultKafkaClusterId: d.Get("default_kafka_cluster_id").(string),
+				clusterAliases:        clusterAliases,
+			}
+			c.useGonfluent(client, topicApi)
+			if d.Get("refresh_roles").(bool) {
+				roles, err := listRoles(c.rest)
+				if err != nil {
+					tflog.SubsystemWarn(ctx, logMDS, "Cannot read the roles, the predefined roles are used", map[string]interface{}{"error": err.Error()})
+					diags = append(diags, warningDiags("Cannot read the roles of MDS",
+						"The role bindings are validated with the predefined roles of Confluent Platform: "+errorDetail(err), "refresh_roles")...)
+				} else {
+					c.roles = roleMatrixOf(roles)
+				}
+			}
+			return c, diags
+		}
 
+		tflog.SubsystemWarn(ctx, logMDS, "Cannot login to Confluent Server, trying the next bootstrap server", map[string]interface{}{"url": baseUrl, "error": err.Error()})
 	}
 
 	return nil, diag.FromErr(fmt.Errorf("cannot find any Kafka Nodes available in the list of bootstrap server"))
@@ -190,7 +388,6 @@ func dTos(key string, d *schema.ResourceData) *[]string {
 
 		for i, vv := range vI {
 			if vv == nil {
-				log.Printf("[DEBUG] %d %v was nil", i, vv)
 				continue
 			}
 			b[i] = vv.(string)
@@ -199,4 +396,4 @@ func dTos(key string, d *schema.ResourceData) *[]string {
 	}
 
 	return r
-}
\ No newline at end of file
+}
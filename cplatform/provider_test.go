@@ -1,6 +1,9 @@
 package cplatform
 
 import (
+	"context"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,3 +18,32 @@ func TestProvider(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 }
+
+func TestProviderConfigure_restTopicApi(t *testing.T) {
+	f := newFakeConfluent(t)
+	// the bootstrap server is the HTTPS port of the fake, no broker answers the Kafka protocol there
+	srv := httptest.NewTLSServer(f)
+	t.Cleanup(srv.Close)
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"username":          fakeUsername,
+		"password":          fakePassword,
+		"bootstrap_servers": []interface{}{strings.TrimPrefix(srv.URL, "https://")},
+		"topic_api":         "rest",
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("configure: %v", diags)
+	}
+
+	c := meta.(*Client)
+	if _, ok := c.topics.(restTopicAPI); !ok {
+		t.Errorf("the topics backend is %T, expected restTopicAPI", c.topics)
+	}
+	if _, err := c.kafkaAdmin(); err != errNoKafka {
+		t.Errorf("kafkaAdmin() = %v, expected %v", err, errNoKafka)
+	}
+	if err := c.topics.CreateTopic(fakeClusterId, "payments", 1, 1, nil, nil); err != nil {
+		t.Errorf("cannot create a topic with the REST API: %v", err)
+	}
+}
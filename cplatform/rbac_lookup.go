@@ -0,0 +1,156 @@
+package cplatform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	lookupPath = "/security/1.0/lookup/"
+)
+
+// rbacScope is a cluster on which the role bindings are looked up
+type rbacScope struct {
+	ClusterType string
+	ClusterId   string
+	Details     confluent.ClusterDetails
+}
+
+// rbacScopeSchema are the arguments of the data sources to choose the clusters where to look up
+func rbacScopeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cluster_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The ID of Kafka cluster",
+		},
+		"schema_registry_cluster_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The ID of Schema Registry cluster",
+		},
+		"connect_cluster_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The ID of Kafka Connect cluster",
+		},
+		"ksql_cluster_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The ID of ksqlDB cluster",
+		},
+	}
+}
+
+// rbacScopes returns the Kafka cluster and the given Schema Registry/Connect/ksqlDB clusters.
+// When none of them is given, every cluster bound to the Kafka cluster in the MDS cluster registry is used.
+func rbacScopes(ctx context.Context, c restAPI, d *schema.ResourceData) ([]rbacScope, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	clusterId := d.Get("cluster_id").(string)
+
+	kafka := rbacScope{ClusterType: "Kafka", ClusterId: clusterId}
+	kafka.Details.Clusters.KafkaCluster = clusterId
+	scopes := []rbacScope{kafka}
+
+	sub := confluent.Clusters{
+		SchemaRegistryCluster: d.Get("schema_registry_cluster_id").(string),
+		ConnectCluster:        d.Get("connect_cluster_id").(string),
+		KSqlCluster:           d.Get("ksql_cluster_id").(string),
+	}
+	subClusters := []confluent.Clusters{sub}
+
+	if sub == (confluent.Clusters{}) {
+		subClusters = nil
+		registry, err := listRegisteredClusters(c)
+		if err != nil {
+			tflog.SubsystemWarn(ctx, logMDS, "Cannot read the cluster registry", map[string]interface{}{"cluster_id": clusterId, "error": err.Error()})
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Cannot read the MDS cluster registry",
+				Detail:   "Only the Kafka cluster " + clusterId + " is looked up: " + err.Error(),
+			})
+		}
+		for _, v := range registry {
+			if v.Scope.Clusters.KafkaCluster == clusterId && !isKafkaClusterScope(v.Scope) {
+				subClusters = append(subClusters, v.Scope.Clusters)
+			}
+		}
+	}
+
+	for _, v := range subClusters {
+		if v.SchemaRegistryCluster != "" {
+			s := rbacScope{ClusterType: "SchemaRegistry", ClusterId: v.SchemaRegistryCluster}
+			s.Details.Clusters.KafkaCluster = clusterId
+			s.Details.Clusters.SchemaRegistryCluster = v.SchemaRegistryCluster
+			scopes = append(scopes, s)
+		}
+		if v.ConnectCluster != "" {
+			s := rbacScope{ClusterType: "Connect", ClusterId: v.ConnectCluster}
+			s.Details.Clusters.KafkaCluster = clusterId
+			s.Details.Clusters.ConnectCluster = v.ConnectCluster
+			scopes = append(scopes, s)
+		}
+		if v.KSqlCluster != "" {
+			s := rbacScope{ClusterType: "KSQL", ClusterId: v.KSqlCluster}
+			s.Details.Clusters.KafkaCluster = clusterId
+			s.Details.Clusters.KSqlCluster = v.KSqlCluster
+			scopes = append(scopes, s)
+		}
+	}
+
+	return scopes, diags
+}
+
+// lookupPrincipalRoleNames returns the roles bound to the principal at the given scope
+func lookupPrincipalRoleNames(c restAPI, principal string, cDetails confluent.ClusterDetails) ([]string, error) {
+	u := lookupPath + "principals/" + url.PathEscape(principal) + "/roleNames"
+
+	var roles []string
+	if err := doLookup(c, u, cDetails, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// lookupPrincipalResources returns the resources bound to the principal at the given scope, by principal then by role.
+// The bindings of the groups of the principal are returned as well, keyed by the group.
+func lookupPrincipalResources(c restAPI, principal string, cDetails confluent.ClusterDetails) (map[string]map[string][]confluent.ResourcePattern, error) {
+	u := lookupPath + "principal/" + url.PathEscape(principal) + "/resources"
+
+	var resources map[string]map[string][]confluent.ResourcePattern
+	if err := doLookup(c, u, cDetails, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// lookupResourcePrincipals returns the principals which hold the role on the resource at the given scope
+func lookupResourcePrincipals(c restAPI, role, resourceType, name string, cDetails confluent.ClusterDetails) ([]string, error) {
+	u := lookupPath + "role/" + url.PathEscape(role) + "/resource/" + url.PathEscape(resourceType) + "/name/" + url.PathEscape(name)
+
+	var principals []string
+	if err := doLookup(c, u, cDetails, &principals); err != nil {
+		return nil, err
+	}
+	return principals, nil
+}
+
+func doLookup(c restAPI, u string, cDetails confluent.ClusterDetails, out interface{}) error {
+	payloadBuf := new(bytes.Buffer)
+	if err := json.NewEncoder(payloadBuf).Encode(cDetails); err != nil {
+		return err
+	}
+
+	r, err := c.DoRequest("POST", u, payloadBuf)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(r, out)
+}
@@ -0,0 +1,194 @@
+package cplatform
+
+import (
+	"context"
+	"strings"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// rbacSubClusterIdAttributes are the attributes of the cluster of the scope of the RBAC resources by resource type,
+// the resources of Kafka are only scoped to cluster_id
+var rbacSubClusterIdAttributes = map[string]string{
+	"Subject":   "schema_registry_cluster_id",
+	"Connector": "connect_cluster_id",
+}
+
+// resourcePatternBinding is a resource pattern in the role binding of a principal, what kafka_topic_rbac,
+// schema_registry_rbac and connectors_rbac manage
+type resourcePatternBinding struct {
+	id        string
+	principal string
+	role      string
+	scope     confluent.ClusterDetails
+	pattern   confluent.ResourcePattern
+}
+
+// newResourcePatternBinding is the binding of a resource being created, its cluster IDs are resolved and set.
+// resourceType is the type of the pattern, the resource_type attribute is used when it is empty.
+func newResourcePatternBinding(c *Client, d *schema.ResourceData, resourceType string) (resourcePatternBinding, diag.Diagnostics) {
+	if resourceType == "" {
+		resourceType = d.Get("resource_type").(string)
+	}
+	var sub []string
+	if k, ok := rbacSubClusterIdAttributes[resourceType]; ok {
+		sub = []string{k}
+	}
+	ids, diags := c.setClusterIds(d, sub)
+	if diags != nil {
+		return resourcePatternBinding{}, diags
+	}
+
+	principal := normalizePrincipal(d.Get("principal").(string))
+	name, patternType := d.Get("name").(string), d.Get("pattern_type").(string)
+	if len(sub) == 0 {
+		return parseResourcePatternBinding(buildId(ids["cluster_id"], principal, d.Get("role").(string), resourceType, name, patternType), resourceType)
+	}
+	return parseResourcePatternBinding(buildId(ids["cluster_id"], ids[sub[0]], principal, d.Get("role").(string), name, patternType), resourceType)
+}
+
+// parseResourcePatternBinding returns the binding of a resource ID, which has the real IDs of the clusters:
+// cluster_id|principal|role|resource_type|name|pattern_type for the resources of Kafka, resourceType is then empty, else
+// cluster_id|<ID of the cluster of resourceType>|principal|role|name|pattern_type
+func parseResourcePatternBinding(id, resourceType string) (resourcePatternBinding, diag.Diagnostics) {
+	b := resourcePatternBinding{id: id}
+	k, ok := rbacSubClusterIdAttributes[resourceType]
+	if !ok {
+		p, err := parseId("Kafka topic RBAC", id, "cluster_id", "principal", "role", "resource_type", "name", "pattern_type")
+		if err != nil {
+			return b, diag.FromErr(err)
+		}
+		b.scope.Clusters.KafkaCluster, b.principal, b.role = p[0], p[1], p[2]
+		b.pattern = confluent.ResourcePattern{ResourceType: p[3], Name: p[4], PatternType: p[5]}
+		return b, nil
+	}
+
+	p, err := parseId(resourceType+" RBAC", id, "cluster_id", k, "principal", "role", "name", "pattern_type")
+	if err != nil {
+		return b, diag.FromErr(err)
+	}
+	b.scope.Clusters.KafkaCluster, b.principal, b.role = p[0], p[2], p[3]
+	switch resourceType {
+	case "Subject":
+		b.scope.Clusters.SchemaRegistryCluster = p[1]
+	case "Connector":
+		b.scope.Clusters.ConnectCluster = p[1]
+	}
+	b.pattern = confluent.ResourcePattern{ResourceType: resourceType, Name: p[4], PatternType: p[5]}
+	return b, nil
+}
+
+// setClusterIds sets the cluster IDs of the resource from the binding, a state written before the aliases were
+// resolved has the aliases
+func (b resourcePatternBinding) setClusterIds(d *schema.ResourceData) error {
+	ids := map[string]string{"cluster_id": b.scope.Clusters.KafkaCluster}
+	switch b.pattern.ResourceType {
+	case "Subject":
+		ids["schema_registry_cluster_id"] = b.scope.Clusters.SchemaRegistryCluster
+	case "Connector":
+		ids["connect_cluster_id"] = b.scope.Clusters.ConnectCluster
+	}
+	for k, id := range ids {
+		if err := d.Set(k, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b resourcePatternBinding) description() string {
+	return strings.ToLower(b.pattern.ResourceType) + " " + b.pattern.Name
+}
+
+func (b resourcePatternBinding) logFields() map[string]interface{} {
+	return map[string]interface{}{
+		"cluster_id":    b.scope.Clusters.KafkaCluster,
+		"principal":     b.principal,
+		"role":          b.role,
+		"resource_type": b.pattern.ResourceType,
+		"name":          b.pattern.Name,
+	}
+}
+
+// rbacLogSubsystem is the subsystem of the logs of the resources of the resource type
+func rbacLogSubsystem(resourceType string) string {
+	if resourceType == "Subject" {
+		return logSchemaRegistry
+	}
+	return logMDS
+}
+
+// resourcePatternCreate adds the resource pattern to the role binding of the principal, a pattern which is already
+// bound fails: it has to be imported so that destroying the resource does not remove a binding Terraform did not add
+func resourcePatternCreate(resourceType string) schema.CreateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		c := meta.(*Client).roleBindings
+		b, diags := newResourcePatternBinding(meta.(*Client), d, resourceType)
+		if diags != nil {
+			return diags
+		}
+
+		if found, err := hasResourcePattern(c, b.principal, b.role, b.scope, b.pattern); err == nil && found {
+			return roleAlreadyBoundDiags(b.principal, b.role, b.pattern.ResourceType, b.pattern.Name, b.id)
+		}
+
+		u := confluent.RoleBinding{
+			Scope:            b.scope,
+			ResourcePatterns: []confluent.ResourcePattern{b.pattern},
+		}
+		if err := c.IncreaseRoleBinding(b.principal, b.role, u); err != nil {
+			return errorDiags("Cannot add "+b.description()+" to the role binding of "+b.principal, err, "role")
+		}
+		d.SetId(b.id)
+		return nil
+	}
+}
+
+// resourcePatternRead removes the resource from the state when the pattern is no longer in the role binding
+func resourcePatternRead(resourceType string) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		c := meta.(*Client).roleBindings
+		b, diags := parseResourcePatternBinding(d.Id(), resourceType)
+		if diags != nil {
+			return diags
+		}
+
+		found, err := hasResourcePattern(c, b.principal, b.role, b.scope, b.pattern)
+		if err != nil && !isNotFound(err) {
+			fields := b.logFields()
+			fields["error"] = err.Error()
+			tflog.SubsystemError(ctx, rbacLogSubsystem(resourceType), "Cannot look up the role binding", fields)
+			return errorDiags("Cannot look up the role binding of "+b.principal, err, "principal")
+		}
+
+		if !found {
+			tflog.SubsystemWarn(ctx, rbacLogSubsystem(resourceType), "The resource has been removed from the role binding, re-create it", b.logFields())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(b.setClusterIds(d))
+	}
+}
+
+// resourcePatternDelete removes the resource pattern from the role binding of the principal
+func resourcePatternDelete(resourceType string) schema.DeleteContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		c := meta.(*Client).roleBindings
+		b, diags := parseResourcePatternBinding(d.Id(), resourceType)
+		if diags != nil {
+			return diags
+		}
+
+		u := confluent.RoleBinding{
+			Scope:            b.scope,
+			ResourcePatterns: []confluent.ResourcePattern{b.pattern},
+		}
+		if err := c.DecreaseRoleBinding(b.principal, b.role, u); err != nil {
+			return errorDiags("Cannot remove "+b.description()+" from the role binding of "+b.principal, err, "role")
+		}
+		return nil
+	}
+}
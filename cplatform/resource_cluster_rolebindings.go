@@ -3,28 +3,17 @@ package cplatform
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	confluent "github.com/OneMount/gonfluent"
 )
 
 var (
-	validRole = []string{
-		"AuditAdmin",
-		"ClusterAdmin",
-		"DeveloperManage",
-		"DeveloperRead",
-		"DeveloperWrite",
-		"Operator",
-		"ResourceOwner",
-		"SecurityAdmin",
-		"SystemAdmin",
-		"UserAdmin",
-	}
+	// scopeRole are the predefined roles of the resources, see defaultRoleMatrix for the other roles
 	scopeRole = []string{
 		"DeveloperRead",
 		"DeveloperWrite",
@@ -53,34 +42,38 @@ var (
 //
 //   provider = confluent-kafka.confluent
 //	}
-// Resource ID = cluster_type + "|" + clusterId + "|" + principal + "|" + role
+// Resource ID = cluster_type|cluster_id|<ID of the cluster of cluster_type, empty for Kafka>|principal|role
 func clusterRoleBindings() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: clusterRoleBindingsCreate,
 		DeleteContext: clusterRoleBindingsDelete,
 		ReadContext:   clusterRoleBindingsRead,
+		CustomizeDiff: customdiff.Sequence(
+			clusterIdsCustomizeDiff(func(d *schema.ResourceDiff) []string {
+				if k, ok := subClusterIdAttributes[d.Get("cluster_type").(string)]; ok {
+					return []string{k}
+				}
+				return nil
+			}),
+			roleScopeCustomizeDiff(func(d *schema.ResourceDiff) (string, string, bool) {
+				return d.Get("cluster_type").(string), "", d.NewValueKnown("cluster_type")
+			}),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: clusterRoleBindingsImport,
+		},
+
+		SchemaVersion:  resourceIdVersion,
+		StateUpgraders: []schema.StateUpgrader{idStateUpgrader(schemaV0("principal", "role", "cluster_type", "cluster_id", "schema_registry_cluster_id", "connect_cluster_id", "ksql_cluster_id"), upgradeClusterRoleBindingId)},
 
 		Schema: map[string]*schema.Schema{
-			"principal": {
+			"principal": principalSchema(),
+			"role": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
 				Required:    true,
-				Description: "Enable communication with the Kafka Cluster over TLS.",
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(string)
-					if !strings.Contains(v, "User:") || !strings.Contains(v, "Group:") && strings.Contains(v, "|") {
-						errs = append(errs, fmt.Errorf("%q must be defined with USER: or GROUP: and must not have |, got: %s", key, v))
-					}
-					return
-				},
-			},
-			"role": {
-				Type:         schema.TypeString,
-				ForceNew:     true,
-				Required:     true,
-				Description:  "Role of",
-				DefaultFunc:  schema.EnvDefaultFunc("ROLE", "DeveloperRead"),
-				ValidateFunc: validation.StringInSlice(validRole, false),
+				Description: "Role of",
+				DefaultFunc: schema.EnvDefaultFunc("ROLE", "DeveloperRead"),
 			},
 			"cluster_type": {
 				Type:         schema.TypeString,
@@ -91,191 +84,182 @@ func clusterRoleBindings() *schema.Resource {
 			"cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
-				Required:    true,
-				Description: "The ID of cluster",
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of Kafka cluster, or an alias of the provider clusters. The default_kafka_cluster_id of the provider when it is not set",
 			},
 			"schema_registry_cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
 				Optional:    true,
-				Description: "The ID of cluster",
+				Computed:    true,
+				Description: "The ID of cluster, or an alias of the provider clusters. The one of the alias of cluster_id when it is not set and cluster_type needs it",
 			},
 			"connect_cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
 				Optional:    true,
-				Description: "The ID of cluster",
+				Computed:    true,
+				Description: "The ID of cluster, or an alias of the provider clusters. The one of the alias of cluster_id when it is not set and cluster_type needs it",
 			},
 			"ksql_cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
 				Optional:    true,
-				Description: "The ID of cluster",
+				Computed:    true,
+				Description: "The ID of cluster, or an alias of the provider clusters. The one of the alias of cluster_id when it is not set and cluster_type needs it",
 			},
 		},
 	}
 }
 
-func clusterRoleBindingsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
+func clusterRoleBindingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).rest
 
-	var (
-		clusterType string
-		subClusterId string
-	)
-
-	f, err := filterClusterTypeWithClusterId(d)
+	clusterType, clusterId, subClusterId, principal, role, err := parseClusterRoleBindingId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
-
-	clusterId := parseIdToResourcesList(d.Id())[1]
-	principal := parseIdToResourcesList(d.Id())[2]
-	role := parseIdToResourcesList(d.Id())[3]
-
-	t := strings.Split(parseIdToResourcesList(d.Id())[0], ":")
-	if len(t) < 2 {
-		clusterType = parseIdToResourcesList(d.Id())[0]
-	} else {
-		clusterType = t[0]
-		subClusterId = t[1]
+	cd := clusterRoleBindingScope(clusterType, clusterId, subClusterId)
+
+	roles, err := lookupPrincipalRoleNames(c, principal, cd)
+	if err != nil && !isNotFound(err) {
+		diags := errorDiags("Cannot look up the role binding of "+principal, err, "principal")
+		tflog.SubsystemError(ctx, logMDS, "Cannot look up the role binding", map[string]interface{}{
+			"cluster_id":   clusterId,
+			"cluster_type": clusterType,
+			"principal":    principal,
+			"role":         role,
+			"error":        err.Error(),
+		})
+		return diags
 	}
 
-	cd := confluent.ClusterDetails{}
-	cd.Clusters.KafkaCluster = clusterId
-
-	switch clusterType {
-	case "Kafka":
-
-	case "SchemaRegistry":
-		if !(contains(f, "schema_registry_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: schema_registry_cluster_id"))
-		}
-		cd.Clusters.SchemaRegistryCluster = subClusterId
-	case "Connect":
-		if !(contains(f, "connect_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: connect_cluster_id"))
-		}
-		cd.Clusters.ConnectCluster = subClusterId
-	case "KSQL":
-		if !(contains(f, "ksql_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: ksql_cluster_id"))
-		}
-		cd.Clusters.KSqlCluster = subClusterId
+	if !contains(roles, role) {
+		tflog.SubsystemWarn(ctx, logMDS, "The role binding has been removed, re-create it", map[string]interface{}{
+			"cluster_id":   clusterId,
+			"cluster_type": clusterType,
+			"principal":    principal,
+			"role":         role,
+		})
+		d.SetId("")
+		return nil
 	}
 
-	_, err = c.LookupRoleBinding(principal, role, cd)
-	if err != nil {
-		log.Printf("[ERROR] Error getting role-binding %s from Confluent", err)
+	// a state written before the aliases were resolved has the aliases
+	if err := d.Set("cluster_id", clusterId); err != nil {
 		return diag.FromErr(err)
 	}
-
+	if k, ok := subClusterIdAttributes[clusterType]; ok {
+		if err := d.Set(k, subClusterId); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	return nil
 }
 
-func clusterRoleBindingsCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
+func clusterRoleBindingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).roleBindings
 
-	f, err := filterClusterTypeWithClusterId(d)
-	if err != nil {
-		return diag.FromErr(err)
+	if _, err := filterClusterTypeWithClusterId(d); err != nil {
+		return diag.Diagnostics{newDiagnostic(diag.Error, "Conflicting cluster IDs", err.Error()+", only the ID of the cluster of cluster_type can be set", "cluster_type")}
 	}
 
-	clusterId := d.Get("cluster_id").(string)
-	principal := d.Get("principal").(string)
+	principal := normalizePrincipal(d.Get("principal").(string))
 	role := d.Get("role").(string)
 	clusterType := d.Get("cluster_type").(string)
-	cd := confluent.ClusterDetails{}
-	cd.Clusters.KafkaCluster = clusterId
-
-	var rId string
-	switch clusterType {
-	case "Kafka":
-		rId = clusterType + "|" + clusterId + "|" + principal + "|" + role
-	case "SchemaRegistry":
-		if !(contains(f, "schema_registry_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: schema_registry_cluster_id"))
-		}
-		cd.Clusters.SchemaRegistryCluster = d.Get("schema_registry_cluster_id").(string)
-		rId = clusterType + ":" + d.Get("schema_registry_cluster_id").(string) + "|" + clusterId + "|" + principal + "|" + role
-	case "Connect":
-		if !(contains(f, "connect_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: connect_cluster_id"))
-		}
-		cd.Clusters.ConnectCluster = d.Get("connect_cluster_id").(string)
-		rId = clusterType + ":" + d.Get("connect_cluster_id").(string) + "|" + clusterId + "|" + principal + "|" + role
-	case "KSQL":
-		if !(contains(f, "ksql_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: ksql_cluster_id"))
-		}
-		cd.Clusters.KSqlCluster = d.Get("ksql_cluster_id").(string)
-		rId = clusterType + ":" + d.Get("ksql_cluster_id").(string) + "|" + clusterId + "|" + principal + "|" + role
+	var sub []string
+	if k, ok := subClusterIdAttributes[clusterType]; ok {
+		sub = []string{k}
+	}
+	ids, diags := meta.(*Client).setClusterIds(d, sub)
+	if diags != nil {
+		return diags
+	}
+	var subClusterId string
+	if len(sub) > 0 {
+		subClusterId = ids[sub[0]]
 	}
 
-	err = c.BindPrincipalToRole(principal, role, cd)
+	err := c.BindPrincipalToRole(principal, role, clusterRoleBindingScope(clusterType, ids["cluster_id"], subClusterId))
 	if err != nil {
-		return diag.FromErr(err)
+		return errorDiags("Cannot bind the role "+role+" to "+principal, err, "role")
 	}
-	d.SetId(rId)
+	d.SetId(buildId(clusterType, ids["cluster_id"], subClusterId, principal, role))
 	return nil
 }
 
-func clusterRoleBindingsDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	var (
-		clusterType string
-		subClusterId string
-	)
+func clusterRoleBindingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).roleBindings
 
-	f, err := filterClusterTypeWithClusterId(d)
+	clusterType, clusterId, subClusterId, principal, role, err := parseClusterRoleBindingId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	clusterId := parseIdToResourcesList(d.Id())[1]
-	principal := parseIdToResourcesList(d.Id())[2]
-	role := parseIdToResourcesList(d.Id())[3]
-
-	t := strings.Split(parseIdToResourcesList(d.Id())[0], ":")
-	if len(t) < 2 {
-		clusterType = parseIdToResourcesList(d.Id())[0]
-	} else {
-		clusterType = t[0]
-		subClusterId = t[1]
+	err = c.DeleteRoleBinding(principal, role, clusterRoleBindingScope(clusterType, clusterId, subClusterId))
+	if err != nil {
+		return errorDiags("Cannot delete the role binding of "+principal+" to "+role, err, "role")
 	}
 
+	return nil
+}
+
+// clusterRoleBindingScope is the scope of a role binding at the cluster of cluster_type
+func clusterRoleBindingScope(clusterType, clusterId, subClusterId string) confluent.ClusterDetails {
 	cd := confluent.ClusterDetails{}
 	cd.Clusters.KafkaCluster = clusterId
-
 	switch clusterType {
-	case "Kafka":
 	case "SchemaRegistry":
-		if !(contains(f, "schema_registry_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: schema_registry_cluster_id"))
-		}
 		cd.Clusters.SchemaRegistryCluster = subClusterId
 	case "Connect":
-		if !(contains(f, "connect_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: connect_cluster_id"))
-		}
 		cd.Clusters.ConnectCluster = subClusterId
 	case "KSQL":
-		if !(contains(f, "ksql_cluster_id")) {
-			return diag.FromErr(fmt.Errorf("miss parameter: ksql_cluster_id"))
-		}
 		cd.Clusters.KSqlCluster = subClusterId
 	}
+	return cd
+}
 
-	err = c.DeleteRoleBinding(principal, role, cd)
+func parseClusterRoleBindingId(id string) (string, string, string, string, string, error) {
+	p, err := parseId("cluster role binding", id, "cluster_type", "cluster_id", "sub_cluster_id", "principal", "role")
 	if err != nil {
-		diag.FromErr(err)
+		return "", "", "", "", "", err
 	}
+	return p[0], p[1], p[2], p[3], p[4], nil
+}
 
-	return nil
+// clusterRoleBindingsImport sets the attributes from the ID, the ID of the cluster of cluster_type goes to its attribute
+func clusterRoleBindingsImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	clusterType, clusterId, subClusterId, principal, role, err := parseClusterRoleBindingId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]string{"cluster_type": clusterType, "cluster_id": clusterId, "principal": principal, "role": role}
+	if k, ok := subClusterIdAttributes[clusterType]; ok {
+		attributes[k] = subClusterId
+	}
+	for k, v := range attributes {
+		if err := d.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
-func parseIdToResourcesList(bindId string) []string {
-	return strings.Split(bindId, "|")
+// upgradeClusterRoleBindingId upgrades cluster_type[:sub_cluster_id]|cluster_id|principal|role
+func upgradeClusterRoleBindingId(rawState map[string]interface{}) string {
+	var subClusterId string
+	switch stateString(rawState, "cluster_type") {
+	case "SchemaRegistry":
+		subClusterId = stateString(rawState, "schema_registry_cluster_id")
+	case "Connect":
+		subClusterId = stateString(rawState, "connect_cluster_id")
+	case "KSQL":
+		subClusterId = stateString(rawState, "ksql_cluster_id")
+	}
+	return buildId(stateString(rawState, "cluster_type"), stateString(rawState, "cluster_id"), subClusterId,
+		normalizePrincipal(stateString(rawState, "principal")), stateString(rawState, "role"))
 }
 
 func filterClusterTypeWithClusterId(d *schema.ResourceData) ([]string, error) {
@@ -0,0 +1,135 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccClusterRoleBinding_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	kafka := confluent.ClusterDetails{}
+	kafka.Clusters.KafkaCluster = fakeClusterId
+	connect := confluent.ClusterDetails{}
+	connect.Clusters.KafkaCluster = fakeClusterId
+	connect.Clusters.ConnectCluster = "connect-cluster"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			f.testAccCheckClusterRoleBinding("User:alice", "Operator", kafka, false),
+			f.testAccCheckClusterRoleBinding("User:alice", "SystemAdmin", connect, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccClusterRoleBindingConfig("Operator"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckClusterRoleBinding("User:alice", "Operator", kafka, true),
+					f.testAccCheckClusterRoleBinding("User:alice", "SystemAdmin", connect, true),
+					resource.TestCheckResourceAttr("cluster_role_binding.kafka", "id", "Kafka|"+fakeClusterId+"||User:alice|Operator"),
+					resource.TestCheckResourceAttr("cluster_role_binding.connect", "id", "Connect|"+fakeClusterId+"|connect-cluster|User:alice|SystemAdmin"),
+				),
+			},
+			// role is ForceNew, the binding is replaced
+			{
+				Config: testAccClusterRoleBindingConfig("ClusterAdmin"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckClusterRoleBinding("User:alice", "Operator", kafka, false),
+					f.testAccCheckClusterRoleBinding("User:alice", "ClusterAdmin", kafka, true),
+				),
+			},
+			{
+				ResourceName:      "cluster_role_binding.kafka",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// the ID of the Connect cluster goes to connect_cluster_id
+			{
+				ResourceName:      "cluster_role_binding.connect",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func (f *fakeConfluent) testAccCheckClusterRoleBinding(principal, role string, cd confluent.ClusterDetails, exists bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if f.hasBinding(principal, role, cd, nil) != exists {
+			return fmt.Errorf("role binding %s of %s at %s: exists should be %t", role, principal, scopeKey(cd), exists)
+		}
+		return nil
+	}
+}
+
+func testAccClusterRoleBindingConfig(role string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "cluster_role_binding" "kafka" {
+  principal    = "User:alice"
+  role         = %q
+  cluster_id   = %q
+  cluster_type = "Kafka"
+
+  provider = confluent
+}
+
+resource "cluster_role_binding" "connect" {
+  principal          = "User:alice"
+  role               = "SystemAdmin"
+  cluster_id         = %[2]q
+  cluster_type       = "Connect"
+  connect_cluster_id = "connect-cluster"
+
+  provider = confluent
+}
+`, role, fakeClusterId)
+}
+
+// recordingRoleBindings is a roleBindingAdmin keeping the bindings in memory
+type recordingRoleBindings struct {
+	roleBindingAdmin
+	bound   []string
+	deleted []string
+}
+
+func (r *recordingRoleBindings) BindPrincipalToRole(principal, roleName string, cDetails confluent.ClusterDetails) error {
+	r.bound = append(r.bound, principal+"|"+roleName+"|"+cDetails.Clusters.ConnectCluster)
+	return nil
+}
+
+func (r *recordingRoleBindings) DeleteRoleBinding(principal, roleName string, cDetails confluent.ClusterDetails) error {
+	r.deleted = append(r.deleted, principal+"|"+roleName+"|"+cDetails.Clusters.ConnectCluster)
+	return nil
+}
+
+func TestClusterRoleBindings_roleBindingAdmin(t *testing.T) {
+	bindings := &recordingRoleBindings{}
+	c := &Client{roleBindings: bindings}
+
+	d := schema.TestResourceDataRaw(t, clusterRoleBindings().Schema, map[string]interface{}{
+		"principal":          "User:alice",
+		"role":               "SystemAdmin",
+		"cluster_id":         fakeClusterId,
+		"cluster_type":       "Connect",
+		"connect_cluster_id": "connect-cluster",
+	})
+	if diags := clusterRoleBindingsCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if diags := clusterRoleBindingsDelete(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+
+	want := []string{"User:alice|SystemAdmin|connect-cluster"}
+	if !reflect.DeepEqual(bindings.bound, want) || !reflect.DeepEqual(bindings.deleted, want) {
+		t.Errorf("bound %v and deleted %v, want %v", bindings.bound, bindings.deleted, want)
+	}
+}
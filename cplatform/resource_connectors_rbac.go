@@ -1,14 +1,9 @@
 package cplatform
 
 import (
-	"context"
-	"fmt"
-	confluent "github.com/OneMount/gonfluent"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"log"
-	"strings"
 )
 
 // schemaRegistryRBAC define the roles binding for schema registry resources
@@ -24,33 +19,33 @@ resource "connectors_rbac" "example_role_binding_developerwrite_connector" {
 	provider = confluent-kafka.confluent
 }
 */
+// Resource ID = cluster_id|connect_cluster_id|principal|role|name|pattern_type
 func connectorsRBAC() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: connectorsRBACCreate,
-		DeleteContext: connectorsRBACDelete,
-		ReadContext:   connectorsRBACRead,
+		CreateContext: resourcePatternCreate("Connector"),
+		DeleteContext: resourcePatternDelete("Connector"),
+		ReadContext:   resourcePatternRead("Connector"),
+		CustomizeDiff: customdiff.Sequence(
+			clusterIdsCustomizeDiff(func(d *schema.ResourceDiff) []string {
+				return []string{"connect_cluster_id"}
+			}),
+			roleScopeCustomizeDiff(func(d *schema.ResourceDiff) (string, string, bool) {
+				return "Connect", "Connector", true
+			}),
+		),
+		Importer:      importIdAttributes("connectors RBAC", "cluster_id", "connect_cluster_id", "principal", "role", "name", "pattern_type"),
+
+		SchemaVersion:  resourceIdVersion,
+		StateUpgraders: []schema.StateUpgrader{idStateUpgrader(schemaV0("principal", "role", "pattern_type", "name", "cluster_id", "connect_cluster_id"), upgradeConnectorsRBACId)},
 
 		Schema: map[string]*schema.Schema{
-			"principal": {
+			"principal": principalSchema(),
+			"role": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
 				Required:    true,
-				Description: "Defined the principal - User or subject",
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(string)
-					if !strings.Contains(v, "User:") || !strings.Contains(v, "Group:") && strings.Contains(v, "|") {
-						errs = append(errs, fmt.Errorf("%q must be defined with User: or Group: and must not have |, got: %s", key, v))
-					}
-					return
-				},
-			},
-			"role": {
-				Type:         schema.TypeString,
-				ForceNew:     true,
-				Required:     true,
-				Description:  "Role of",
-				DefaultFunc:  schema.EnvDefaultFunc("ROLE", "DeveloperRead"),
-				ValidateFunc: validation.StringInSlice(scopeRole, false),
+				Description: "Role of",
+				DefaultFunc: schema.EnvDefaultFunc("ROLE", "DeveloperRead"),
 			},
 			"pattern_type": {
 				Type:         schema.TypeString,
@@ -66,117 +61,23 @@ func connectorsRBAC() *schema.Resource {
 			"cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
-				Required:    true,
-				Description: "The ID of Kafka cluster",
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of Kafka cluster, or an alias of the provider clusters. The default_kafka_cluster_id of the provider when it is not set",
 			},
 			"connect_cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
-				Required:    true,
-				Description: "The ID of Kafka Connect cluster",
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of Kafka Connect cluster, or an alias of the provider clusters. The one of the alias of cluster_id when it is not set",
 			},
 		},
 	}
 }
 
-func connectorsRBACRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	principal := d.Get("principal").(string)
-	role := d.Get("role").(string)
-	clusterId := d.Get("cluster_id").(string)
-	connectClusterId := d.Get("connect_cluster_id").(string)
-
-	cDetails := &confluent.ClusterDetails{}
-	cDetails.Clusters.KafkaCluster = clusterId
-	cDetails.Clusters.ConnectCluster = connectClusterId
-
-	roleBindings, err := c.LookupRoleBinding(principal, role, *cDetails)
-	if err != nil {
-		log.Printf("[ERROR] Error lookup role-binding %s from Confluent", err)
-		return diag.FromErr(err)
-	}
-
-	r := make(map[string]string)
-
-	for _, v := range roleBindings {
-		if v.Name == d.Get("name").(string) {
-			r[v.ResourceType] = v.PatternType
-		}
-	}
-
-	if r["Connector"] != d.Get("pattern_type").(string) {
-		err = fmt.Errorf("cannot find resource_type Subject of" + d.Get("name").(string))
-		log.Printf("[ERROR] Error lookup role-binding from Confluent %s", err)
-		return diag.FromErr(err)
-	}
-	return nil
-}
-
-func connectorsRBACCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	principal := d.Get("principal").(string)
-	role := d.Get("role").(string)
-	clusterId:= d.Get("cluster_id").(string)
-	connectClusterId := d.Get("connect_cluster_id").(string)
-
-
-	cDetails := &confluent.ClusterDetails{
-		Clusters: confluent.Clusters{
-			KafkaCluster: clusterId,
-			ConnectCluster: connectClusterId,
-		},
-	}
-
-	u := confluent.RoleBinding{
-		Scope: *cDetails,
-		ResourcePatterns: []confluent.ResourcePattern{
-			{
-				ResourceType: "Connector",
-				Name:         d.Get("name").(string),
-				PatternType:  d.Get("pattern_type").(string),
-			},
-		},
-	}
-
-	err = c.IncreaseRoleBinding(principal, role, u)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	rId := clusterId + "|ConnectClusterId:" + connectClusterId + "|" + principal + "|" + role + "|Connector|" + d.Get("name").(string) + "|" + d.Get("pattern_type").(string)
-	d.SetId(rId)
-	return nil
-}
-
-func connectorsRBACDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	principal := d.Get("principal").(string)
-	role := d.Get("role").(string)
-	clusterId:= d.Get("cluster_id").(string)
-	connectClusterId := d.Get("connect_cluster_id").(string)
-
-	cDetails := &confluent.ClusterDetails{
-		Clusters: confluent.Clusters{
-			KafkaCluster: clusterId,
-			ConnectCluster: connectClusterId,
-		},
-	}
-
-	u := confluent.RoleBinding{
-		Scope: *cDetails,
-		ResourcePatterns: []confluent.ResourcePattern{
-			{
-				ResourceType: "Subject",
-				Name:         d.Get("name").(string),
-				PatternType:  d.Get("pattern_type").(string),
-			},
-		},
-	}
-
-	err = c.DecreaseRoleBinding(principal, role, u)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	return nil
+// upgradeConnectorsRBACId upgrades cluster_id|ConnectClusterId:connect_cluster_id|principal|role|Connector|name|pattern_type
+func upgradeConnectorsRBACId(rawState map[string]interface{}) string {
+	return buildId(stateString(rawState, "cluster_id"), stateString(rawState, "connect_cluster_id"), normalizePrincipal(stateString(rawState, "principal")),
+		stateString(rawState, "role"), stateString(rawState, "name"), stateString(rawState, "pattern_type"))
 }
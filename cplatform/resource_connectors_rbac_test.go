@@ -0,0 +1,65 @@
+package cplatform
+
+import (
+	"fmt"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccConnectorsRBAC_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	connect := confluent.ClusterDetails{}
+	connect.Clusters.KafkaCluster = fakeClusterId
+	connect.Clusters.ConnectCluster = "connect-cluster"
+	jdbc := confluent.ResourcePattern{ResourceType: "Connector", Name: "jdbc-", PatternType: "PREFIXED"}
+	s3 := confluent.ResourcePattern{ResourceType: "Connector", Name: "s3-sink", PatternType: "LITERAL"}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			f.testAccCheckResourceRoleBinding("User:alice", "ResourceOwner", connect, jdbc, false),
+			f.testAccCheckResourceRoleBinding("User:alice", "ResourceOwner", connect, s3, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConnectorsRBACConfig("jdbc-", "PREFIXED"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "ResourceOwner", connect, jdbc, true),
+					resource.TestCheckResourceAttr("connectors_rbac.alice", "id", fakeClusterId+"|connect-cluster|User:alice|ResourceOwner|jdbc-|PREFIXED"),
+				),
+			},
+			// name is ForceNew, the binding is replaced
+			{
+				Config: testAccConnectorsRBACConfig("s3-sink", "LITERAL"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "ResourceOwner", connect, jdbc, false),
+					f.testAccCheckResourceRoleBinding("User:alice", "ResourceOwner", connect, s3, true),
+				),
+			},
+			{
+				ResourceName:      "connectors_rbac.alice",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccConnectorsRBACConfig(name, patternType string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "connectors_rbac" "alice" {
+  principal          = "User:alice"
+  role               = "ResourceOwner"
+  name               = %q
+  pattern_type       = %q
+  cluster_id         = %q
+  connect_cluster_id = "connect-cluster"
+
+  provider = confluent
+}
+`, name, patternType, fakeClusterId)
+}
@@ -0,0 +1,113 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceIdVersion is the SchemaVersion of the resources with an ID made of several fields.
+// The IDs of version 0 joined the fields with "|" without escaping them, and some had prefixes like "ConnectClusterId:".
+// The resources added after the escaping never had a version 0, they start at this version without a state upgrader.
+const resourceIdVersion = 1
+
+const resourceIdSeparator = "|"
+
+// idEscaper escapes the fields of the IDs, so that a principal or a name can contain "|"
+var idEscaper = strings.NewReplacer("%", "%25", resourceIdSeparator, "%7C")
+
+// buildId joins the escaped fields of an ID
+func buildId(fields ...string) string {
+	escaped := make([]string, len(fields))
+	for i, f := range fields {
+		escaped[i] = idEscaper.Replace(f)
+	}
+	return strings.Join(escaped, resourceIdSeparator)
+}
+
+// splitId returns the unescaped fields of an ID
+func splitId(id string) ([]string, error) {
+	fields := strings.Split(id, resourceIdSeparator)
+	for i, f := range fields {
+		v, err := url.PathUnescape(f)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = v
+	}
+	return fields, nil
+}
+
+// parseId returns the fields of an ID of the given kind, which must have one field by name.
+// The names are only used to explain the expected format when the ID is malformed.
+func parseId(kind, id string, names ...string) ([]string, error) {
+	fields, err := splitId(id)
+	if err == nil && len(fields) != len(names) {
+		err = fmt.Errorf("%d fields instead of %d", len(fields), len(names))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s ID %q, expected <%s> with \"|\" and \"%%\" escaped as %%7C and %%25: %w",
+			kind, id, strings.Join(names, ">|<"), err)
+	}
+	return fields, nil
+}
+
+// schemaV0 is the frozen schema of version 0 of a resource whose attributes were all strings, the state upgraders decode
+// the states of version 0 with it whatever the current schema of the resource becomes
+func schemaV0(attributes ...string) map[string]*schema.Schema {
+	s := make(map[string]*schema.Schema, len(attributes))
+	for _, a := range attributes {
+		s[a] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	}
+	return s
+}
+
+// idStateUpgrader returns the StateUpgrader of the IDs of version 0 of a resource, v0 is its schema of version 0.
+// The fields of these IDs are ambiguous, so upgrade builds the new ID from the attributes of the state.
+func idStateUpgrader(v0 map[string]*schema.Schema, upgrade func(rawState map[string]interface{}) string) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    (&schema.Resource{Schema: v0}).CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			if rawState == nil {
+				return rawState, nil
+			}
+			id := upgrade(rawState)
+			tflog.Info(ctx, "Upgrading the resource ID", map[string]interface{}{"old_id": rawState["id"], "id": id})
+			rawState["id"] = id
+			return rawState, nil
+		},
+	}
+}
+
+// stateString returns an attribute of a raw state, or "" when it is not set
+func stateString(rawState map[string]interface{}, key string) string {
+	v, _ := rawState[key].(string)
+	return v
+}
+
+// importIdAttributes returns the importer of a resource whose ID is made of the given attributes, in order: they are
+// set from the fields of the ID, except the empty ones, then Read checks that the resource exists.
+func importIdAttributes(kind string, attributes ...string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+			fields, err := parseId(kind, d.Id(), attributes...)
+			if err != nil {
+				return nil, err
+			}
+			for i, a := range attributes {
+				if fields[i] == "" {
+					continue
+				}
+				if err := d.Set(a, fields[i]); err != nil {
+					return nil, err
+				}
+			}
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package cplatform
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceId_escaping(t *testing.T) {
+	fields := []string{"cluster", "User:CN=alice|ops,OU=eng", "100%", ""}
+	id := buildId(fields...)
+	if id != "cluster|User:CN=alice%7Cops,OU=eng|100%25|" {
+		t.Errorf("buildId() = %s", id)
+	}
+	got, err := parseId("test", id, "a", "b", "c", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, fields) {
+		t.Errorf("parseId() = %q, want %q", got, fields)
+	}
+
+	for _, malformed := range []string{"cluster|principal", "a|b|c|d|e", "a|b|%zz|d"} {
+		if _, err := parseId("test", malformed, "a", "b", "c", "d"); err == nil || !strings.Contains(err.Error(), "<a>|<b>|<c>|<d>") {
+			t.Errorf("parseId(%s) = %v, expected an error with the format", malformed, err)
+		}
+	}
+}
+
+// the names of the Kafka resources are not validated against |, their IDs escape it
+func TestResourceId_kafkaNamesWithPipe(t *testing.T) {
+	name := "ops|alice"
+	e, err := parseQuotaId(quotaId(quotaEntity{Type: "user", Name: &name}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != "user" || e.Name == nil || *e.Name != name {
+		t.Errorf("parseQuotaId() = %+v, want the user %s", e, name)
+	}
+
+	username, mechanism, err := parseScramCredentialId(buildId(name, "SCRAM-SHA-512"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != name || mechanism != "SCRAM-SHA-512" {
+		t.Errorf("parseScramCredentialId() = %s, %s, want %s, SCRAM-SHA-512", username, mechanism, name)
+	}
+
+	r := kafkaConsumerGroupOffsets()
+	d := r.TestResourceData()
+	d.SetId(buildId("app|v2", "payments|eu"))
+	ds, err := r.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group, topic := ds[0].Get("group_id"), ds[0].Get("topic"); group != "app|v2" || topic != "payments|eu" {
+		t.Errorf("imported the group %s and the topic %s, want app|v2 and payments|eu", group, topic)
+	}
+}
+
+func TestResourceId_stateUpgraders(t *testing.T) {
+	cases := []struct {
+		name     string
+		upgrade  func(context.Context, map[string]interface{}, interface{}) (map[string]interface{}, error)
+		rawState map[string]interface{}
+		meta     interface{}
+		id       string
+	}{
+		{
+			name:    "cluster_role_binding",
+			upgrade: clusterRoleBindings().StateUpgraders[0].Upgrade,
+			rawState: map[string]interface{}{
+				"id":                 "Connect:connect|kafka|User:alice|SystemAdmin",
+				"principal":          "User:alice",
+				"role":               "SystemAdmin",
+				"cluster_type":       "Connect",
+				"cluster_id":         "kafka",
+				"connect_cluster_id": "connect",
+			},
+			id: "Connect|kafka|connect|User:alice|SystemAdmin",
+		},
+		{
+			name:    "connectors_rbac",
+			upgrade: connectorsRBAC().StateUpgraders[0].Upgrade,
+			rawState: map[string]interface{}{
+				"id":                 "kafka|ConnectClusterId:connect|User:a|b|ResourceOwner|Connector|jdbc-|PREFIXED",
+				"principal":          "User:a|b",
+				"role":               "ResourceOwner",
+				"cluster_id":         "kafka",
+				"connect_cluster_id": "connect",
+				"name":               "jdbc-",
+				"pattern_type":       "PREFIXED",
+			},
+			id: "kafka|connect|User:a%7Cb|ResourceOwner|jdbc-|PREFIXED",
+		},
+		{
+			name:    "kafka_topic with an alias",
+			upgrade: topics().StateUpgraders[0].Upgrade,
+			rawState: map[string]interface{}{
+				"id":         "payments",
+				"name":       "payments",
+				"partitions": 1,
+				"cluster_id": "prod",
+			},
+			meta: fakeAliasClient(),
+			id:   buildId(fakeClusterId, "payments"),
+		},
+	}
+
+	for _, c := range cases {
+		state, err := c.upgrade(context.Background(), c.rawState, c.meta)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if state["id"] != c.id {
+			t.Errorf("%s: upgraded ID %s, want %s", c.name, state["id"], c.id)
+		}
+	}
+}
+
+func TestResourceId_versions(t *testing.T) {
+	// the resources added with the escaped IDs have no version 0 to upgrade
+	for name, r := range map[string]*schema.Resource{
+		"kafka_quota":                  kafkaQuota(),
+		"kafka_scram_credential":       kafkaScramCredential(),
+		"kafka_consumer_group_offsets": kafkaConsumerGroupOffsets(),
+		"kafka_cluster_link":           kafkaClusterLink(),
+		"kafka_mirror_topic":           kafkaMirrorTopic(),
+	} {
+		if r.SchemaVersion != resourceIdVersion || len(r.StateUpgraders) != 0 {
+			t.Errorf("%s: version %d with %d state upgraders, want version %d without upgrader", name, r.SchemaVersion, len(r.StateUpgraders), resourceIdVersion)
+		}
+	}
+
+	// the state of version 0 is decoded with the frozen schema, not with the current one
+	v0 := (&schema.Resource{Schema: schemaV0("principal", "role", "resource_type", "pattern_type", "name", "cluster_id")}).CoreConfigSchema().ImpliedType()
+	if upgrader := kafkaTopicRBAC().StateUpgraders[0]; !upgrader.Type.Equals(v0) {
+		t.Errorf("kafka_topic_rbac decodes the version 0 as %s, want %s", upgrader.Type.GoString(), v0.GoString())
+	}
+}
+
+func TestClusterRoleBindingsRead_malformedId(t *testing.T) {
+	d := clusterRoleBindings().TestResourceData()
+	d.SetId("Kafka|User:alice")
+	if diags := clusterRoleBindingsRead(context.Background(), d, &Client{}); !diags.HasError() {
+		t.Error("expected an error reading a malformed ID")
+	}
+}
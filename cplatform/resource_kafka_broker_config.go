@@ -0,0 +1,303 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultBroker = "<default>"
+)
+
+// kafkaBrokerConfig manages the dynamic configs of a broker, or the cluster-wide default of every broker
+// when broker_id is not set
+// example:
+//
+//	resource "kafka_broker_config" "cluster" {
+//	  config = {
+//	    "log.cleaner.threads" = "2"
+//	  }
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// Only the configs set in the resource are managed, the other dynamic configs are neither read nor changed.
+// An import only manages the configs listed in its ID, like Read.
+// Resource ID = broker_id, <default> for the cluster-wide configs
+func kafkaBrokerConfig() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: kafkaBrokerConfigCreate,
+		ReadContext:   kafkaBrokerConfigRead,
+		UpdateContext: kafkaBrokerConfigUpdate,
+		DeleteContext: kafkaBrokerConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: kafkaBrokerConfigImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"broker_id": {
+				Type:        schema.TypeString,
+				ForceNew:    true,
+				Optional:    true,
+				Description: "The ID of the broker. The configs are the cluster-wide default when it is not set",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(string)
+					if _, err := strconv.ParseInt(v, 10, 32); err != nil {
+						errs = append(errs, fmt.Errorf("%q must be a broker ID, got: %s", key, v))
+					}
+					return
+				},
+			},
+			"config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "The dynamic configs, for example log.cleaner.threads. The value of a config Kafka reports as sensitive is kept as configured, set it in sensitive_config instead",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"sensitive_config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The dynamic configs which Kafka does not return, for example the passwords of the listener keystores. Only their removal is detected",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// kafkaBrokerConfigImport manages the configs listed in the import ID <broker_id>|<config>,<config>, Read then only
+// keeps them as for any other state. The sensitive configs cannot be imported since Kafka does not return them.
+func kafkaBrokerConfigImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	fields, err := splitId(d.Id())
+	if err != nil || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid broker config import ID %q, expected <broker_id> or <broker_id>|<config>,<config>", d.Id())
+	}
+	brokerId, err := parseBrokerConfigId(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if len(fields) == 2 && fields[1] != "" {
+		names = strings.Split(fields[1], ",")
+	}
+
+	admin, err := meta.(*Client).kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := describeBrokerConfigs(admin, brokerId)
+	if err != nil {
+		return nil, err
+	}
+	config := make(map[string]interface{})
+	for _, e := range entries {
+		if !contains(names, e.Name) {
+			continue
+		}
+		if e.Sensitive {
+			return nil, fmt.Errorf("%s is sensitive, Kafka does not return its value: set it in sensitive_config instead of importing it", e.Name)
+		}
+		config[e.Name] = e.Value
+	}
+	for _, n := range names {
+		if _, ok := config[n]; !ok {
+			return nil, fmt.Errorf("%s is not a dynamic config of the broker %s", n, brokerConfigId(brokerId))
+		}
+	}
+
+	d.SetId(brokerConfigId(brokerId))
+	if err := d.Set("config", config); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func kafkaBrokerConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	admin, err := meta.(*Client).kafkaAdmin()
+	if err != nil {
+		return errorDiags("Cannot connect to Kafka", err, "")
+	}
+
+	brokerId, err := parseBrokerConfigId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	entries, err := describeBrokerConfigs(admin, brokerId)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot describe the configs of the broker", map[string]interface{}{"broker_id": brokerId, "error": err.Error()})
+		return errorDiags("Cannot describe the configs of the broker "+brokerId, err, "broker_id")
+	}
+
+	// Only the configs in the state are read, the ones set outside of the resource are not a drift.
+	// Kafka does not return the value of a sensitive config, the one of the state is kept until the config is removed.
+	managed := d.Get("config").(map[string]interface{})
+	sensitive := d.Get("sensitive_config").(map[string]interface{})
+	config := make(map[string]interface{})
+	sensitiveConfig := make(map[string]interface{})
+	for _, e := range entries {
+		if e.Sensitive {
+			if v, ok := sensitive[e.Name]; ok {
+				sensitiveConfig[e.Name] = v
+			}
+			if v, ok := managed[e.Name]; ok {
+				config[e.Name] = v
+			}
+			continue
+		}
+		if _, ok := managed[e.Name]; ok {
+			config[e.Name] = e.Value
+		}
+	}
+
+	if err := d.Set("broker_id", brokerId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("config", config); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("sensitive_config", sensitiveConfig); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func kafkaBrokerConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	config, err := brokerConfigOf(d)
+	if err != nil {
+		return errorDiags("Invalid configs of the broker", err, "config")
+	}
+
+	entries := make(map[string]sarama.IncrementalAlterConfigsEntry)
+	for k, v := range config {
+		v := v
+		entries[k] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &v}
+	}
+
+	brokerId := d.Get("broker_id").(string)
+	tflog.SubsystemInfo(ctx, logKafka, "Setting the configs of the broker", map[string]interface{}{"broker_id": brokerId})
+	if err := c.incrementalAlterConfigs(sarama.ConfigResource{Type: sarama.BrokerResource, Name: brokerId}, entries); err != nil {
+		return errorDiags("Cannot alter the configs of the broker "+d.Get("broker_id").(string), err, "config")
+	}
+
+	d.SetId(brokerConfigId(brokerId))
+	return kafkaBrokerConfigRead(ctx, d, meta)
+}
+
+func kafkaBrokerConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	config, err := brokerConfigOf(d)
+	if err != nil {
+		return errorDiags("Invalid configs of the broker", err, "config")
+	}
+
+	// The configs removed from the resource are deleted, the state only has the configs it manages
+	entries := make(map[string]sarama.IncrementalAlterConfigsEntry)
+	for _, key := range []string{"config", "sensitive_config"} {
+		o, _ := d.GetChange(key)
+		for k := range o.(map[string]interface{}) {
+			if _, ok := config[k]; !ok {
+				entries[k] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+			}
+		}
+	}
+	for k, v := range config {
+		v := v
+		entries[k] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &v}
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Updating the configs of the broker", map[string]interface{}{"broker_id": d.Get("broker_id").(string)})
+	if err := c.incrementalAlterConfigs(sarama.ConfigResource{Type: sarama.BrokerResource, Name: d.Get("broker_id").(string)}, entries); err != nil {
+		return errorDiags("Cannot alter the configs of the broker "+d.Get("broker_id").(string), err, "config")
+	}
+
+	return kafkaBrokerConfigRead(ctx, d, meta)
+}
+
+func kafkaBrokerConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	entries := make(map[string]sarama.IncrementalAlterConfigsEntry)
+	for _, key := range []string{"config", "sensitive_config"} {
+		for k := range d.Get(key).(map[string]interface{}) {
+			entries[k] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+		}
+	}
+
+	if err := c.incrementalAlterConfigs(sarama.ConfigResource{Type: sarama.BrokerResource, Name: d.Get("broker_id").(string)}, entries); err != nil {
+		return errorDiags("Cannot alter the configs of the broker "+d.Get("broker_id").(string), err, "config")
+	}
+
+	return nil
+}
+
+// describeBrokerConfigs returns the dynamic configs set for the broker, or the cluster-wide ones when brokerId is empty
+func describeBrokerConfigs(admin sarama.ClusterAdmin, brokerId string) ([]sarama.ConfigEntry, error) {
+	entries, err := admin.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.BrokerResource,
+		Name: brokerId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A config set for the broker hides the cluster-wide default
+	source := sarama.SourceDynamicBroker
+	if brokerId == "" {
+		source = sarama.SourceDynamicDefaultBroker
+	}
+	var dynamic []sarama.ConfigEntry
+	for _, e := range entries {
+		if e.Source == source {
+			dynamic = append(dynamic, e)
+		}
+	}
+	return dynamic, nil
+}
+
+// brokerConfigOf merges config and sensitive_config, a config must not be in both
+func brokerConfigOf(d *schema.ResourceData) (map[string]string, error) {
+	config := make(map[string]string)
+	for k, v := range d.Get("config").(map[string]interface{}) {
+		config[k] = v.(string)
+	}
+	for k, v := range d.Get("sensitive_config").(map[string]interface{}) {
+		if _, ok := config[k]; ok {
+			return nil, fmt.Errorf("%s is in both config and sensitive_config", k)
+		}
+		config[k] = v.(string)
+	}
+	return config, nil
+}
+
+func brokerConfigId(brokerId string) string {
+	if brokerId == "" {
+		return defaultBroker
+	}
+	return brokerId
+}
+
+func parseBrokerConfigId(id string) (string, error) {
+	if id == defaultBroker {
+		return "", nil
+	}
+	if _, err := strconv.ParseInt(id, 10, 32); err != nil {
+		return "", fmt.Errorf("invalid broker config ID %q, expected <broker_id> or %s", id, defaultBroker)
+	}
+	return id, nil
+}
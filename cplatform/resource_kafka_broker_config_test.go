@@ -0,0 +1,205 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaBrokerConfig_basic(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	// set outside of terraform
+	k.brokerConfigs[""] = map[string]string{"log.cleaner.backoff.ms": "30000"}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: k.providerFactories(t),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			k.testAccCheckBrokerConfigs("", map[string]string{"log.cleaner.backoff.ms": "30000"}),
+			k.testAccCheckBrokerConfigs("1", nil),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaBrokerConfigConfig(`
+    "log.cleaner.threads"        = "2"
+    "log.cleaner.io.buffer.size" = "1048576"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckBrokerConfigs("", map[string]string{"log.cleaner.threads": "2", "log.cleaner.io.buffer.size": "1048576", "log.cleaner.backoff.ms": "30000"}),
+					resource.TestCheckResourceAttr("kafka_broker_config.cluster", "config.%", "2"),
+					k.testAccCheckBrokerConfigs("1", map[string]string{"log.cleaner.threads": "4", "listener.name.internal.ssl.key.password": "key-secret"}),
+					resource.TestCheckResourceAttr("kafka_broker_config.cluster", "id", "<default>"),
+					resource.TestCheckResourceAttr("kafka_broker_config.broker", "id", "1"),
+				),
+			},
+			// the removed config is deleted from Kafka
+			{
+				Config: testAccKafkaBrokerConfigConfig(`
+    "log.cleaner.threads" = "3"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckBrokerConfigs("", map[string]string{"log.cleaner.threads": "3", "log.cleaner.backoff.ms": "30000"}),
+					resource.TestCheckResourceAttr("kafka_broker_config.cluster", "config.%", "1"),
+				),
+			},
+			// the import only manages the configs of its ID
+			{
+				ResourceName:      "kafka_broker_config.cluster",
+				ImportState:       true,
+				ImportStateId:     "<default>|log.cleaner.threads",
+				ImportStateVerify: true,
+			},
+			// Kafka does not return the sensitive configs
+			{
+				ResourceName:            "kafka_broker_config.broker",
+				ImportState:             true,
+				ImportStateId:           "1|log.cleaner.threads",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"sensitive_config"},
+			},
+		},
+	})
+}
+
+func TestKafkaBrokerConfig_otherConfigs(t *testing.T) {
+	ctx := context.Background()
+	k := newFakeKafka(t, newFakeConfluent(t))
+	c := k.client(t, "")
+	r := kafkaBrokerConfig()
+
+	apply := func(state *terraform.InstanceState, config map[string]interface{}) *terraform.InstanceState {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{"config": config}), c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state, diags := r.Apply(ctx, state, diff, c)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		return state
+	}
+
+	state := apply(nil, map[string]interface{}{"log.cleaner.threads": "2", "log.cleaner.backoff.ms": "15000"})
+	k.brokerConfigs[""]["log.retention.ms"] = "86400000"
+
+	state, diags := r.RefreshWithoutUpgrade(ctx, state, c)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if v, ok := state.Attributes["config.log.retention.ms"]; ok {
+		t.Errorf("the config set outside of the resource is in the state: %s", v)
+	}
+
+	apply(state, map[string]interface{}{"log.cleaner.threads": "3"})
+	if err := k.testAccCheckBrokerConfigs("", map[string]string{"log.cleaner.threads": "3", "log.retention.ms": "86400000"})(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKafkaBrokerConfig_sensitiveAndImport(t *testing.T) {
+	ctx := context.Background()
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.brokerConfigs[""] = map[string]string{"log.retention.ms": "86400000"}
+	c := k.client(t, "")
+	r := kafkaBrokerConfig()
+
+	// a sensitive config in config keeps its configured value
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"config": map[string]interface{}{
+		"log.cleaner.threads":                     "2",
+		"listener.name.internal.ssl.key.password": "key-secret",
+	}})
+	diff, err := r.Diff(ctx, nil, config, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, diags := r.Apply(ctx, nil, diff, c)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state, diags = r.RefreshWithoutUpgrade(ctx, state, c); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diff, err = r.Diff(ctx, state, config, c); err != nil || diff != nil && len(diff.Attributes) > 0 {
+		t.Errorf("expected no drift of the sensitive config, got %v, %v", diff, err)
+	}
+
+	importState := func(id string) (map[string]string, error) {
+		d := r.Data(&terraform.InstanceState{ID: id})
+		states, err := r.Importer.StateContext(ctx, d, c)
+		if err != nil {
+			return nil, err
+		}
+		s, diags := r.RefreshWithoutUpgrade(ctx, states[0].State(), c)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		return s.Attributes, nil
+	}
+
+	// only the configs of the ID are imported, as Read only keeps the configs of the state
+	attributes, err := importState("<default>|log.cleaner.threads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attributes["id"] != "<default>" || attributes["config.%"] != "1" || attributes["config.log.cleaner.threads"] != "2" {
+		t.Errorf("unexpected imported state %v", attributes)
+	}
+	if attributes, err = importState("<default>"); err != nil || attributes["config.%"] != "0" {
+		t.Errorf("expected no config to be imported, got %v, %v", attributes, err)
+	}
+
+	for id, want := range map[string]string{
+		"<default>|listener.name.internal.ssl.key.password": "set it in sensitive_config",
+		"<default>|log.segment.bytes":                       "is not a dynamic config",
+		"<default>|log.cleaner.threads|x":                   "invalid broker config import ID",
+	} {
+		if _, err := importState(id); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("import %s: got %v, expected %q", id, err, want)
+		}
+	}
+}
+
+// testAccCheckBrokerConfigs checks the dynamic configs of the broker, nil when it must have none
+func (k *fakeKafka) testAccCheckBrokerConfigs(brokerId string, configs map[string]string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		k.sync()
+		k.mu.Lock()
+		defer k.mu.Unlock()
+
+		actual := k.brokerConfigs[brokerId]
+		if len(actual) == 0 && len(configs) == 0 {
+			return nil
+		}
+		if !reflect.DeepEqual(actual, configs) {
+			return fmt.Errorf("configs of the broker %q are %v, expected %v", brokerId, actual, configs)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaBrokerConfigConfig(config string) string {
+	return fakeProviderConfig + `
+resource "kafka_broker_config" "cluster" {
+  config = {` + config + `  }
+
+  provider = confluent
+}
+
+resource "kafka_broker_config" "broker" {
+  broker_id = "1"
+  config = {
+    "log.cleaner.threads" = "4"
+  }
+  sensitive_config = {
+    "listener.name.internal.ssl.key.password" = "key-secret"
+  }
+
+  provider = confluent
+}
+`
+}
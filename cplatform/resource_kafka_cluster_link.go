@@ -0,0 +1,281 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	// sourceLinkConfigs are the link configs managed by the source_* attributes
+	sourceLinkConfigs = []string{
+		"bootstrap.servers",
+		"security.protocol",
+		"sasl.mechanism",
+		"sasl.jaas.config",
+	}
+)
+
+// kafkaClusterLink manages a cluster link from a source cluster to the cluster
+// example:
+//
+//	resource "kafka_cluster_link" "dr" {
+//	  cluster_id               = "destination-cluster-id"
+//	  link_name                = "dr"
+//	  source_cluster_id        = "source-cluster-id"
+//	  source_bootstrap_servers = ["source-1:9092"]
+//	  source_username          = "link"
+//	  source_password          = var.link_password
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// Resource ID = cluster_id|link_name
+func kafkaClusterLink() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: kafkaClusterLinkCreate,
+		ReadContext:   kafkaClusterLinkRead,
+		UpdateContext: kafkaClusterLinkUpdate,
+		DeleteContext: kafkaClusterLinkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: resourceIdVersion,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:        schema.TypeString,
+				ForceNew:    true,
+				Required:    true,
+				Description: "The ID of the destination cluster",
+			},
+			"link_name": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"source_cluster_id": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"source_bootstrap_servers": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"source_security_protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "SASL_SSL",
+				ValidateFunc: validation.StringInSlice([]string{"PLAINTEXT", "SSL", "SASL_PLAINTEXT", "SASL_SSL"}, false),
+			},
+			"source_sasl_mechanism": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "PLAIN",
+				ValidateFunc: validation.StringInSlice([]string{"PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"}, false),
+			},
+			"source_username": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"source_password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "The other configs of the link, for example consumer.offset.sync.enable",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"link_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func kafkaClusterLinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).rest
+
+	clusterId, linkName, err := parseClusterLinkId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	link, err := getClusterLink(c, clusterId, linkName)
+	if err != nil {
+		if isNotFound(err) {
+			tflog.SubsystemWarn(ctx, logKafka, "The cluster link has been removed, re-create it", map[string]interface{}{"cluster_id": clusterId, "link_name": linkName})
+			d.SetId("")
+			return nil
+		}
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the cluster link", map[string]interface{}{"cluster_id": clusterId, "link_name": linkName, "error": err.Error()})
+		return errorDiags("Cannot read the cluster link "+linkName, err, "link_name")
+	}
+	configs, err := listClusterLinkConfigs(c, clusterId, linkName)
+	if err != nil {
+		return errorDiags("Cannot read the configs of the cluster link "+linkName, err, "config")
+	}
+
+	if err := d.Set("cluster_id", clusterId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("link_name", link.LinkName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("source_cluster_id", link.SourceClusterId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("link_id", link.LinkId); err != nil {
+		return diag.FromErr(err)
+	}
+	if v := configs["bootstrap.servers"].Value; v != nil {
+		if err := d.Set("source_bootstrap_servers", strings.Split(*v, ",")); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if v := configs["security.protocol"].Value; v != nil {
+		if err := d.Set("source_security_protocol", *v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if v := configs["sasl.mechanism"].Value; v != nil {
+		if err := d.Set("source_sasl_mechanism", *v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Only the configs set in the resource are read back
+	config := make(map[string]interface{})
+	for k, v := range d.Get("config").(map[string]interface{}) {
+		current, ok := configs[k]
+		switch {
+		case !ok:
+		case current.Sensitive || current.Value == nil:
+			config[k] = v
+		default:
+			config[k] = *current.Value
+		}
+	}
+	if err := d.Set("config", config); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func kafkaClusterLinkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).rest
+
+	clusterId := d.Get("cluster_id").(string)
+	linkName := d.Get("link_name").(string)
+
+	configs, err := clusterLinkConfigsOf(d)
+	if err != nil {
+		return errorDiags("Invalid configs of the cluster link", err, "config")
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Creating the cluster link", map[string]interface{}{
+		"cluster_id":        clusterId,
+		"link_name":         linkName,
+		"source_cluster_id": d.Get("source_cluster_id").(string),
+	})
+	if err := createClusterLink(c, clusterId, linkName, d.Get("source_cluster_id").(string), configs); err != nil {
+		return errorDiags("Cannot create the cluster link "+linkName, err, "link_name")
+	}
+
+	d.SetId(buildId(clusterId, linkName))
+	return kafkaClusterLinkRead(ctx, d, meta)
+}
+
+func kafkaClusterLinkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).rest
+
+	configs, err := clusterLinkConfigsOf(d)
+	if err != nil {
+		return errorDiags("Invalid configs of the cluster link", err, "config")
+	}
+
+	var remove []string
+	o, _ := d.GetChange("config")
+	for k := range o.(map[string]interface{}) {
+		if _, ok := configs[k]; !ok {
+			remove = append(remove, k)
+		}
+	}
+	if d.Get("source_username").(string) == "" {
+		remove = append(remove, "sasl.jaas.config")
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Updating the configs of the cluster link", map[string]interface{}{"cluster_id": d.Get("cluster_id").(string), "link_name": d.Get("link_name").(string)})
+	if err := alterClusterLinkConfigs(c, d.Get("cluster_id").(string), d.Get("link_name").(string), configs, remove); err != nil {
+		return errorDiags("Cannot update the configs of the cluster link "+d.Get("link_name").(string), err, "config")
+	}
+
+	return kafkaClusterLinkRead(ctx, d, meta)
+}
+
+func kafkaClusterLinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).rest
+
+	if err := deleteClusterLink(c, d.Get("cluster_id").(string), d.Get("link_name").(string)); err != nil {
+		return errorDiags("Cannot delete the cluster link "+d.Get("link_name").(string), err, "link_name")
+	}
+
+	return nil
+}
+
+// clusterLinkConfigsOf returns the configs of the link, the source_* attributes included
+func clusterLinkConfigsOf(d *schema.ResourceData) (map[string]string, error) {
+	configs := make(map[string]string)
+	for k, v := range d.Get("config").(map[string]interface{}) {
+		if contains(sourceLinkConfigs, k) {
+			return nil, fmt.Errorf("%s must be set with the source_* attributes, not in config", k)
+		}
+		configs[k] = v.(string)
+	}
+
+	var servers []string
+	for _, v := range d.Get("source_bootstrap_servers").([]interface{}) {
+		servers = append(servers, v.(string))
+	}
+	configs["bootstrap.servers"] = strings.Join(servers, ",")
+	configs["security.protocol"] = d.Get("source_security_protocol").(string)
+
+	if username := d.Get("source_username").(string); username != "" {
+		mechanism := d.Get("source_sasl_mechanism").(string)
+		module := "org.apache.kafka.common.security.plain.PlainLoginModule"
+		if strings.HasPrefix(mechanism, "SCRAM") {
+			module = "org.apache.kafka.common.security.scram.ScramLoginModule"
+		}
+		configs["sasl.mechanism"] = mechanism
+		configs["sasl.jaas.config"] = fmt.Sprintf("%s required username=%q password=%q;", module, username, d.Get("source_password").(string))
+	}
+	return configs, nil
+}
+
+func parseClusterLinkId(id string) (string, string, error) {
+	p, err := parseId("cluster link", id, "cluster_id", "link_name")
+	if err != nil {
+		return "", "", err
+	}
+	if p[0] == "" || p[1] == "" {
+		return "", "", fmt.Errorf("invalid cluster link ID %q, the cluster ID and the link name cannot be empty", id)
+	}
+	return p[0], p[1], nil
+}
@@ -0,0 +1,91 @@
+package cplatform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaClusterLink_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy:      f.testAccCheckClusterLinkDestroy("dr"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaClusterLinkConfig("true"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckClusterLinkConfig("dr", "consumer.offset.sync.enable", "true"),
+					f.testAccCheckClusterLinkConfig("dr", "bootstrap.servers", "source-1:9092,source-2:9092"),
+					resource.TestCheckResourceAttr("kafka_cluster_link.dr", "id", fakeClusterId+"|dr"),
+					resource.TestCheckResourceAttr("kafka_cluster_link.dr", "link_id", "link-id-dr"),
+					resource.TestCheckResourceAttr("kafka_cluster_link.dr", "source_security_protocol", "SASL_SSL"),
+				),
+			},
+			{
+				Config: testAccKafkaClusterLinkConfig("false"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckClusterLinkConfig("dr", "consumer.offset.sync.enable", "false"),
+					resource.TestCheckResourceAttr("kafka_cluster_link.dr", "config.consumer.offset.sync.enable", "false"),
+				),
+			},
+			{
+				ResourceName:      "kafka_cluster_link.dr",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the credentials are only in sasl.jaas.config, which is sensitive, and config is only read back when set
+				ImportStateVerifyIgnore: []string{"source_username", "source_password", "config"},
+			},
+		},
+	})
+}
+
+func (f *fakeConfluent) testAccCheckClusterLinkConfig(name, key, value string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		l, ok := f.links[fakeClusterId+"|"+name]
+		if !ok {
+			return fmt.Errorf("cluster link %s does not exist", name)
+		}
+		if v := l.configs[key]; v != value {
+			return fmt.Errorf("cluster link %s has %s %q, expected %q", name, key, v, value)
+		}
+		return nil
+	}
+}
+
+func (f *fakeConfluent) testAccCheckClusterLinkDestroy(name string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if _, ok := f.links[fakeClusterId+"|"+name]; ok {
+			return fmt.Errorf("cluster link %s still exists", name)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaClusterLinkConfig(offsetSync string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "kafka_cluster_link" "dr" {
+  cluster_id               = %q
+  link_name                = "dr"
+  source_cluster_id        = "source-cluster"
+  source_bootstrap_servers = ["source-1:9092", "source-2:9092"]
+  source_username          = "link"
+  source_password          = "link-secret"
+  config = {
+    "consumer.offset.sync.enable" = %q
+  }
+
+  provider = confluent
+}
+`, fakeClusterId, offsetSync)
+}
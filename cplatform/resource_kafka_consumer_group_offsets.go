@@ -0,0 +1,227 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	validResetStrategy = []string{
+		"earliest",
+		"latest",
+		"timestamp",
+		"explicit",
+	}
+)
+
+// kafkaConsumerGroupOffsets resets the offsets of a consumer group on a topic
+// example:
+//
+//	resource "kafka_consumer_group_offsets" "app" {
+//	  group_id       = "app"
+//	  topic          = "payments"
+//	  reset_strategy = "timestamp"
+//	  timestamp      = "2021-09-01T00:00:00Z"
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// The offsets are reset again each time the strategy, the timestamp or the explicit offsets change.
+// An import only reads the committed offsets, the next apply resets them with the strategy of the configuration.
+// Resource ID = group_id|topic
+func kafkaConsumerGroupOffsets() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: kafkaConsumerGroupOffsetsApply,
+		ReadContext:   kafkaConsumerGroupOffsetsRead,
+		UpdateContext: kafkaConsumerGroupOffsetsApply,
+		DeleteContext: kafkaConsumerGroupOffsetsDelete,
+		Importer:      importIdAttributes("consumer group offsets", "group_id", "topic"),
+
+		SchemaVersion: resourceIdVersion,
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"topic": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"reset_strategy": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "One of earliest, latest, timestamp or explicit",
+				ValidateFunc: validation.StringInSlice(validResetStrategy, false),
+			},
+			"timestamp": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "RFC 3339 time, for the timestamp strategy. The offsets are reset to the first message at or after it",
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"offsets": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "The offset of each partition, for the explicit strategy. The other partitions are not changed",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"committed_offsets": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The offsets committed by the group, by partition",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+	}
+}
+
+func kafkaConsumerGroupOffsetsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	group := d.Get("group_id").(string)
+	topic := d.Get("topic").(string)
+
+	if _, err := c.kafkaAdmin(); err != nil {
+		return errorDiags("Cannot connect to Kafka", err, "")
+	}
+	partitions, err := c.adminKafka.Partitions(topic)
+	if err != nil && isNotFound(err) {
+		tflog.SubsystemWarn(ctx, logKafka, "The topic has been removed, re-create the offsets", map[string]interface{}{"group_id": group, "topic": topic})
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the partitions of the topic", map[string]interface{}{"topic": topic, "error": err.Error()})
+		return errorDiags("Cannot read the partitions of the topic "+topic, err, "topic")
+	}
+
+	offsets, err := c.committedOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the offsets of the consumer group", map[string]interface{}{"group_id": group, "topic": topic, "error": err.Error()})
+		return errorDiags("Cannot read the offsets of the consumer group "+group, err, "group_id")
+	}
+
+	committed := make(map[string]interface{})
+	for p, o := range offsets[topic] {
+		committed[strconv.Itoa(int(p))] = int(o)
+	}
+	if err := d.Set("committed_offsets", committed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func kafkaConsumerGroupOffsetsApply(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	group := d.Get("group_id").(string)
+	topic := d.Get("topic").(string)
+
+	g, err := c.describeConsumerGroup(group)
+	if err != nil {
+		return errorDiags("Cannot describe the consumer group "+group, err, "group_id")
+	}
+	if len(g.Members) > 0 {
+		return diag.Diagnostics{newDiagnostic(diag.Error, "Consumer group "+group+" is active",
+			fmt.Sprintf("The consumer group %s is %s with %d members, stop its consumers before resetting the offsets.", group, g.State, len(g.Members)), "group_id")}
+	}
+
+	offsets, err := resolveOffsets(c, d)
+	if err != nil {
+		return errorDiags("Cannot resolve the offsets of the topic "+topic, err, "reset_strategy")
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Resetting the offsets of the consumer group", map[string]interface{}{
+		"group_id":       group,
+		"topic":          topic,
+		"reset_strategy": d.Get("reset_strategy").(string),
+	})
+	if err := c.commitOffsets(group, topic, offsets); err != nil {
+		return errorDiags("Cannot commit the offsets of the consumer group "+group, err, "group_id")
+	}
+
+	d.SetId(buildId(group, topic))
+	return kafkaConsumerGroupOffsetsRead(ctx, d, meta)
+}
+
+// kafkaConsumerGroupOffsetsDelete only removes the resource from the state, the offsets are kept
+func kafkaConsumerGroupOffsetsDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tflog.SubsystemInfo(ctx, logKafka, "The offsets of the consumer group are kept in Kafka", map[string]interface{}{"group_id": d.Get("group_id").(string), "topic": d.Get("topic").(string)})
+	return nil
+}
+
+// resolveOffsets returns the offset of each partition to commit for the reset strategy
+func resolveOffsets(c *Client, d *schema.ResourceData) (map[int32]int64, error) {
+	topic := d.Get("topic").(string)
+	strategy := d.Get("reset_strategy").(string)
+
+	offsets := make(map[int32]int64)
+	if strategy == "explicit" {
+		explicit := d.Get("offsets").(map[string]interface{})
+		if len(explicit) == 0 {
+			return nil, fmt.Errorf("offsets must be set for the explicit strategy")
+		}
+		for k, v := range explicit {
+			p, err := strconv.ParseInt(k, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("offsets: %q is not a partition", k)
+			}
+			offsets[int32(p)] = int64(v.(int))
+		}
+		return offsets, nil
+	}
+
+	var at int64
+	switch strategy {
+	case "earliest":
+		at = sarama.OffsetOldest
+	case "latest":
+		at = sarama.OffsetNewest
+	case "timestamp":
+		v, ok := d.GetOk("timestamp")
+		if !ok {
+			return nil, fmt.Errorf("timestamp must be set for the timestamp strategy")
+		}
+		t, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return nil, err
+		}
+		at = t.UnixNano() / int64(time.Millisecond)
+	}
+
+	partitions, err := c.adminKafka.Partitions(topic)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range partitions {
+		o, err := c.adminKafka.GetOffset(topic, p, at)
+		if err != nil {
+			return nil, fmt.Errorf("partition %d: %w", p, err)
+		}
+		// There is no message after the timestamp
+		if o < 0 {
+			if o, err = c.adminKafka.GetOffset(topic, p, sarama.OffsetNewest); err != nil {
+				return nil, fmt.Errorf("partition %d: %w", p, err)
+			}
+		}
+		offsets[p] = o
+	}
+	return offsets, nil
+}
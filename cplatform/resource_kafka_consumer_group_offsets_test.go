@@ -0,0 +1,80 @@
+package cplatform
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaConsumerGroupOffsets_basic(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 3, nil)
+	for p := int32(0); p < 3; p++ {
+		k.produce("payments", p, int64(10*(p+1)))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: k.providerFactories(t),
+		// the offsets are kept in Kafka
+		CheckDestroy: k.testAccCheckCommittedOffsets("app", "payments", map[int32]int64{0: 5, 1: 20, 2: 30}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaConsumerGroupOffsetsConfig(`reset_strategy = "latest"`),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckCommittedOffsets("app", "payments", map[int32]int64{0: 10, 1: 20, 2: 30}),
+					resource.TestCheckResourceAttr("kafka_consumer_group_offsets.app", "id", "app|payments"),
+					resource.TestCheckResourceAttr("kafka_consumer_group_offsets.app", "committed_offsets.2", "30"),
+				),
+			},
+			// the other partitions are not changed
+			{
+				Config: testAccKafkaConsumerGroupOffsetsConfig(`
+  reset_strategy = "explicit"
+  offsets = {
+    "0" = 5
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckCommittedOffsets("app", "payments", map[int32]int64{0: 5, 1: 20, 2: 30}),
+					resource.TestCheckResourceAttr("kafka_consumer_group_offsets.app", "committed_offsets.0", "5"),
+				),
+			},
+			// the reset strategy is not kept in Kafka
+			{
+				ResourceName:            "kafka_consumer_group_offsets.app",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"reset_strategy", "offsets"},
+			},
+		},
+	})
+}
+
+func (k *fakeKafka) testAccCheckCommittedOffsets(group, topic string, offsets map[int32]int64) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		k.sync()
+		k.mu.Lock()
+		defer k.mu.Unlock()
+
+		if actual := k.groups[group][topic]; !reflect.DeepEqual(actual, offsets) {
+			return fmt.Errorf("offsets of the consumer group %s on %s are %v, expected %v", group, topic, actual, offsets)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaConsumerGroupOffsetsConfig(strategy string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "kafka_consumer_group_offsets" "app" {
+  group_id = "app"
+  topic    = "payments"
+  %s
+
+  provider = confluent
+}
+`, strategy)
+}
@@ -0,0 +1,293 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	validMirrorState = []string{
+		"active",
+		"paused",
+		"promoted",
+		"failed-over",
+	}
+	// mirrorStateActions are the actions moving a mirror topic to a state
+	mirrorStateActions = map[string]string{
+		"active":      "resume",
+		"paused":      "pause",
+		"promoted":    "promote",
+		"failed-over": "failover",
+	}
+)
+
+// kafkaMirrorTopic manages a mirror topic of a cluster link
+// example:
+//
+//	resource "kafka_mirror_topic" "payments" {
+//	  cluster_id   = "destination-cluster-id"
+//	  link_name    = kafka_cluster_link.dr.link_name
+//	  source_topic = "payments"
+//	  state        = "active"
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// Changing state pauses, resumes, promotes or fails over the mirror topic. A promoted or failed-over mirror topic
+// is a regular topic, it cannot be mirrored again.
+// Resource ID = cluster_id|link_name|mirror_topic
+func kafkaMirrorTopic() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: kafkaMirrorTopicCreate,
+		ReadContext:   kafkaMirrorTopicRead,
+		UpdateContext: kafkaMirrorTopicUpdate,
+		DeleteContext: kafkaMirrorTopicDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: resourceIdVersion,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:        schema.TypeString,
+				ForceNew:    true,
+				Required:    true,
+				Description: "The ID of the destination cluster",
+			},
+			"link_name": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"source_topic": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"mirror_topic": {
+				Type:        schema.TypeString,
+				ForceNew:    true,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the mirror topic, source_topic when it is not set",
+			},
+			"state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "active",
+				Description:  "One of active, paused, promoted or failed-over",
+				ValidateFunc: validation.StringInSlice(validMirrorState, false),
+			},
+			"mirror_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status returned by Confluent, for example ACTIVE, PAUSED or STOPPED",
+			},
+			"num_partitions": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func kafkaMirrorTopicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	clusterId, linkName, mirror, err := parseMirrorTopicId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state := d.Get("state").(string)
+	m, err := getMirrorTopic(c.rest, clusterId, linkName, mirror)
+	if err != nil && !isNotFound(err) {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the mirror topic", map[string]interface{}{"cluster_id": clusterId, "link_name": linkName, "topic": mirror, "error": err.Error()})
+		return errorDiags("Cannot read the mirror topic "+mirror, err, "mirror_topic")
+	}
+	if err != nil {
+		// A promoted or failed-over mirror topic may not be a mirror anymore, but the topic is still there
+		if state == "promoted" || state == "failed-over" {
+			if _, err := c.topics.GetTopic(clusterId, mirror); err == nil {
+				return nil
+			}
+		}
+		tflog.SubsystemWarn(ctx, logKafka, "The mirror topic has been removed, re-create it", map[string]interface{}{"cluster_id": clusterId, "link_name": linkName, "topic": mirror})
+		d.SetId("")
+		return nil
+	}
+
+	switch m.MirrorStatus {
+	case "ACTIVE":
+		state = "active"
+	case "PAUSED":
+		state = "paused"
+	case "STOPPED":
+		if state != "promoted" && state != "failed-over" {
+			state = "promoted"
+		}
+	}
+
+	if err := d.Set("cluster_id", clusterId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("link_name", m.LinkName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("source_topic", m.SourceTopicName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mirror_topic", m.MirrorTopicName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("state", state); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mirror_status", m.MirrorStatus); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("num_partitions", m.NumPartitions); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func kafkaMirrorTopicCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	clusterId := d.Get("cluster_id").(string)
+	linkName := d.Get("link_name").(string)
+	sourceTopic := d.Get("source_topic").(string)
+	mirror := d.Get("mirror_topic").(string)
+	if mirror == "" {
+		mirror = sourceTopic
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Creating the mirror topic", map[string]interface{}{
+		"cluster_id":   clusterId,
+		"link_name":    linkName,
+		"source_topic": sourceTopic,
+		"topic":        mirror,
+	})
+	if err := createMirrorTopic(c.rest, clusterId, linkName, sourceTopic, mirror); err != nil {
+		return errorDiags("Cannot create the mirror topic "+mirror, err, "source_topic")
+	}
+	d.SetId(buildId(clusterId, linkName, mirror))
+
+	if state := d.Get("state").(string); state != "active" {
+		if err := setMirrorTopicState(ctx, c.rest, clusterId, linkName, mirror, state); err != nil {
+			return errorDiags("Cannot set the state of the mirror topic "+mirror, err, "state")
+		}
+	}
+
+	return kafkaMirrorTopicRead(ctx, d, meta)
+}
+
+func kafkaMirrorTopicUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	if d.HasChange("state") {
+		o, n := d.GetChange("state")
+		if o == "promoted" || o == "failed-over" {
+			return diag.Diagnostics{newDiagnostic(diag.Error, "Invalid state of the mirror topic",
+				fmt.Sprintf("The mirror topic %s is %s, it cannot be %s again.", d.Get("mirror_topic").(string), o, n), "state")}
+		}
+		if err := setMirrorTopicState(ctx, c.rest, d.Get("cluster_id").(string), d.Get("link_name").(string), d.Get("mirror_topic").(string), n.(string)); err != nil {
+			return errorDiags("Cannot set the state of the mirror topic "+d.Get("mirror_topic").(string), err, "state")
+		}
+	}
+
+	return kafkaMirrorTopicRead(ctx, d, meta)
+}
+
+// kafkaMirrorTopicDelete deletes the mirror topic, so its messages on the destination cluster.
+// A promoted or failed-over mirror topic is a regular topic used by the applications, it is only removed from the state.
+func kafkaMirrorTopicDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	clusterId := d.Get("cluster_id").(string)
+	mirror := d.Get("mirror_topic").(string)
+
+	if diags := topicDeletionDiags(c, mirror, false, false); diags.HasError() {
+		return diags
+	}
+	if state := d.Get("state").(string); state == "promoted" || state == "failed-over" {
+		tflog.SubsystemWarn(ctx, logKafka, "Keeping the topic of the mirror topic", map[string]interface{}{"cluster_id": clusterId, "topic": mirror, "state": state})
+		return warningDiags("The topic "+mirror+" is kept",
+			fmt.Sprintf("The mirror topic is %s, it is a regular topic now. It is only removed from the state, manage it with a kafka_topic or delete it outside of terraform.", state), "state")
+	}
+
+	if err := c.topics.DeleteTopic(clusterId, mirror); err != nil {
+		return errorDiags("Cannot delete the mirror topic "+mirror, err, "mirror_topic")
+	}
+	if err := waitForTopicDelete(ctx, c.topics, mirror, clusterId); err != nil {
+		return errorDiags("Cannot delete the mirror topic "+mirror, err, "mirror_topic")
+	}
+
+	return nil
+}
+
+// setMirrorTopicState runs the action of the state, waiting for the end of a promotion or a failover
+func setMirrorTopicState(ctx context.Context, c restAPI, clusterId, linkName, mirror, state string) error {
+	action := mirrorStateActions[state]
+	tflog.SubsystemInfo(ctx, logKafka, "Running an action on the mirror topic", map[string]interface{}{
+		"cluster_id": clusterId,
+		"link_name":  linkName,
+		"topic":      mirror,
+		"action":     action,
+	})
+	if err := mirrorTopicAction(c, clusterId, linkName, action, mirror); err != nil {
+		return err
+	}
+	if state != "promoted" && state != "failed-over" {
+		return nil
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"Updating"},
+		Target:  []string{"Ready"},
+		Refresh: func() (interface{}, string, error) {
+			m, err := getMirrorTopic(c, clusterId, linkName, mirror)
+			if err != nil && strings.Contains(err.Error(), "404") {
+				return "not-nil", "Ready", nil
+			}
+			if err != nil {
+				return nil, "Error", err
+			}
+			if m.MirrorStatus == "STOPPED" {
+				return m, "Ready", nil
+			}
+			return m, "Updating", nil
+		},
+		Timeout:      120 * time.Second,
+		Delay:        1 * time.Second,
+		PollInterval: 1 * time.Second,
+		MinTimeout:   2 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for mirror topic (%s) to be %s: %s", mirror, state, err)
+	}
+	return nil
+}
+
+func parseMirrorTopicId(id string) (string, string, string, error) {
+	p, err := parseId("mirror topic", id, "cluster_id", "link_name", "mirror_topic")
+	if err != nil {
+		return "", "", "", err
+	}
+	if p[0] == "" || p[1] == "" || p[2] == "" {
+		return "", "", "", fmt.Errorf("invalid mirror topic ID %q, the cluster ID, the link name and the mirror topic cannot be empty", id)
+	}
+	return p[0], p[1], p[2], nil
+}
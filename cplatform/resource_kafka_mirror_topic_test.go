@@ -0,0 +1,134 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaMirrorTopic_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		// the promoted mirror topic is a regular topic, it is kept
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			f.testAccCheckTopic("dr.payments", 3, ""),
+			f.testAccCheckClusterLinkDestroy("dr"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaMirrorTopicConfig("active"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckMirrorStatus("dr", "dr.payments", "ACTIVE"),
+					resource.TestCheckResourceAttr("kafka_mirror_topic.payments", "id", fakeClusterId+"|dr|dr.payments"),
+					resource.TestCheckResourceAttr("kafka_mirror_topic.payments", "num_partitions", "3"),
+				),
+			},
+			{
+				Config: testAccKafkaMirrorTopicConfig("paused"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckMirrorStatus("dr", "dr.payments", "PAUSED"),
+					resource.TestCheckResourceAttr("kafka_mirror_topic.payments", "mirror_status", "PAUSED"),
+				),
+			},
+			{
+				ResourceName:      "kafka_mirror_topic.payments",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccKafkaMirrorTopicConfig("promoted"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckMirrorStatus("dr", "dr.payments", "STOPPED"),
+					resource.TestCheckResourceAttr("kafka_mirror_topic.payments", "state", "promoted"),
+				),
+			},
+		},
+	})
+}
+
+func TestKafkaMirrorTopicDelete(t *testing.T) {
+	f := newFakeConfluent(t)
+	c, err := f.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		state   string
+		protect string
+		err     string
+		deleted bool
+	}{
+		{state: "active", protect: "^dr\\.", err: "protected against deletion"},
+		{state: "promoted"},
+		{state: "failed-over"},
+		{state: "paused", deleted: true},
+	} {
+		f.mu.Lock()
+		f.topics[fakeClusterId+"|dr.payments"] = &fakeTopic{partitions: 3, replicationFactor: 3, configs: make(map[string]string)}
+		f.mu.Unlock()
+		c.protectTopics = nil
+		if tc.protect != "" {
+			c.protectTopics = []*regexp.Regexp{regexp.MustCompile(tc.protect)}
+		}
+
+		d := schema.TestResourceDataRaw(t, kafkaMirrorTopic().Schema, map[string]interface{}{
+			"cluster_id":   fakeClusterId,
+			"link_name":    "dr",
+			"source_topic": "payments",
+			"mirror_topic": "dr.payments",
+			"state":        tc.state,
+		})
+		d.SetId(buildId(fakeClusterId, "dr", "dr.payments"))
+
+		diags := kafkaMirrorTopicDelete(context.Background(), d, c)
+		switch {
+		case tc.err != "" && (!diags.HasError() || !strings.Contains(diags[0].Summary, tc.err)):
+			t.Errorf("%s: got %v, expected %q", tc.state, diags, tc.err)
+		case tc.err == "" && diags.HasError():
+			t.Errorf("%s: %v", tc.state, diags)
+		}
+		if _, ok := f.topic("dr.payments"); ok == tc.deleted {
+			t.Errorf("%s: the topic exists: %t", tc.state, ok)
+		}
+	}
+}
+
+func (f *fakeConfluent) testAccCheckMirrorStatus(link, mirror, status string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		m, ok := f.mirrors[fakeClusterId+"|"+link+"|"+mirror]
+		if !ok {
+			return fmt.Errorf("mirror topic %s of link %s does not exist", mirror, link)
+		}
+		if m.MirrorStatus != status {
+			return fmt.Errorf("mirror topic %s is %s, expected %s", mirror, m.MirrorStatus, status)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaMirrorTopicConfig(state string) string {
+	return testAccKafkaClusterLinkConfig("true") + fmt.Sprintf(`
+resource "kafka_mirror_topic" "payments" {
+  cluster_id   = %q
+  link_name    = kafka_cluster_link.dr.link_name
+  source_topic = "payments"
+  mirror_topic = "dr.payments"
+  state        = %q
+
+  provider = confluent
+}
+`, fakeClusterId, state)
+}
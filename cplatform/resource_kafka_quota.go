@@ -0,0 +1,298 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	defaultQuotaEntity = "<default>"
+)
+
+var (
+	validQuotaEntityType = []string{
+		"user",
+		"client-id",
+		"ip",
+	}
+	// quotaKeys maps the attributes to the quota keys, the byte rates are integers
+	quotaKeys = map[string]bool{
+		"producer_byte_rate":       true,
+		"consumer_byte_rate":       true,
+		"request_percentage":       false,
+		"controller_mutation_rate": false,
+		"connection_creation_rate": false,
+	}
+	// ipQuotaKey is the only quota of the ip entity, the other quotas are only for the users and the client-ids
+	ipQuotaKey = "connection_creation_rate"
+)
+
+// kafkaQuota manages the quotas of a user, a client-id or an ip
+// example:
+//
+//	resource "kafka_quota" "app" {
+//	  entity_type        = "user"
+//	  entity_name        = "app"
+//	  producer_byte_rate = 1048576
+//	  consumer_byte_rate = 2097152
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// Resource ID = entity_type|entity_name, entity_name is <default> for the default entity
+func kafkaQuota() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: kafkaQuotaCreate,
+		ReadContext:   kafkaQuotaRead,
+		UpdateContext: kafkaQuotaUpdate,
+		DeleteContext: kafkaQuotaDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		CustomizeDiff: quotaKeysCustomizeDiff,
+
+		SchemaVersion: resourceIdVersion,
+
+		Schema: map[string]*schema.Schema{
+			"entity_type": {
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Required:     true,
+				Description:  "One of user, client-id or ip",
+				ValidateFunc: validation.StringInSlice(validQuotaEntityType, false),
+			},
+			"entity_name": {
+				Type:        schema.TypeString,
+				ForceNew:    true,
+				Optional:    true,
+				Description: "The name of the user, client-id or ip. The quota applies to the default entity when it is not set",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					v := val.(string)
+					if v == defaultQuotaEntity {
+						errs = append(errs, fmt.Errorf("%q must not be %s, got: %s", key, defaultQuotaEntity, v))
+					}
+					return
+				},
+			},
+			"producer_byte_rate": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The producer throughput in bytes per second",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"consumer_byte_rate": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The consumer throughput in bytes per second",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"request_percentage": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "The percentage of the request handler and network threads time",
+				ValidateFunc: validation.FloatAtLeast(0.01),
+			},
+			"controller_mutation_rate": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "The rate at which topics and partitions can be created or deleted",
+				ValidateFunc: validation.FloatAtLeast(0.01),
+			},
+			"connection_creation_rate": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "The rate of new connections, only for the ip entity which has no other quota",
+				ValidateFunc: validation.FloatAtLeast(0.01),
+			},
+		},
+	}
+}
+
+// quotaKeysCustomizeDiff fails the plan of a quota that Kafka does not accept for the entity type
+func quotaKeysCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	entityType := d.Get("entity_type").(string)
+	if !d.NewValueKnown("entity_type") {
+		return nil
+	}
+	keys := make([]string, 0, len(quotaKeys))
+	for k := range quotaKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := d.GetOk(k); !ok || (k == ipQuotaKey) == (entityType == "ip") {
+			continue
+		}
+		if entityType == "ip" {
+			return fmt.Errorf("%s cannot be set for the ip entity, it only accepts %s", k, ipQuotaKey)
+		}
+		return fmt.Errorf("%s can only be set for the ip entity, not for %s", k, entityType)
+	}
+	return nil
+}
+
+func kafkaQuotaRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+
+	entity, err := parseQuotaId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values, err := c.clientQuotas.describeClientQuotas(ctx, entity)
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot describe the quotas", map[string]interface{}{"entity_type": entity.Type, "entity_name": entity.Name, "error": err.Error()})
+		return errorDiags("Cannot describe the quotas of "+entity.Type, err, "entity_name")
+	}
+	if len(values) == 0 {
+		tflog.SubsystemWarn(ctx, logKafka, "The quotas have been removed, re-create them", map[string]interface{}{"entity_type": entity.Type, "entity_name": entity.Name})
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("entity_type", entity.Type); err != nil {
+		return diag.FromErr(err)
+	}
+	name := ""
+	if entity.Name != nil {
+		name = *entity.Name
+	}
+	if err := d.Set("entity_name", name); err != nil {
+		return diag.FromErr(err)
+	}
+	for k, isInt := range quotaKeys {
+		v, ok := values[k]
+		switch {
+		case !ok:
+			err = d.Set(k, nil)
+		case isInt:
+			err = d.Set(k, int(v))
+		default:
+			err = d.Set(k, v)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func kafkaQuotaCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	entity := quotaEntityOf(d)
+
+	set := make(map[string]float64)
+	for k := range quotaKeys {
+		if v, ok := quotaValue(d, k); ok {
+			set[k] = v
+		}
+	}
+	if len(set) == 0 {
+		return diag.Diagnostics{newDiagnostic(diag.Error, "No quota", "At least one quota must be set.", "")}
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Setting the quotas", map[string]interface{}{"entity_type": entity.Type, "entity_name": d.Get("entity_name").(string)})
+	if err := c.clientQuotas.alterClientQuotas(ctx, entity, set, nil); err != nil {
+		return errorDiags("Cannot set the quotas of "+entity.Type, err, "")
+	}
+
+	d.SetId(quotaId(entity))
+	return kafkaQuotaRead(ctx, d, meta)
+}
+
+func kafkaQuotaUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	entity := quotaEntityOf(d)
+
+	set := make(map[string]float64)
+	var remove []string
+	isSet := false
+	for k := range quotaKeys {
+		v, ok := quotaValue(d, k)
+		isSet = isSet || ok
+		if !d.HasChange(k) {
+			continue
+		}
+		if ok {
+			set[k] = v
+		} else {
+			remove = append(remove, k)
+		}
+	}
+	if !isSet {
+		return diag.Diagnostics{newDiagnostic(diag.Error, "No quota", "At least one quota must be set.", "")}
+	}
+
+	if err := c.clientQuotas.alterClientQuotas(ctx, entity, set, remove); err != nil {
+		return errorDiags("Cannot update the quotas of "+entity.Type, err, "")
+	}
+
+	return kafkaQuotaRead(ctx, d, meta)
+}
+
+func kafkaQuotaDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client)
+	entity := quotaEntityOf(d)
+
+	remove := make([]string, 0, len(quotaKeys))
+	for k := range quotaKeys {
+		remove = append(remove, k)
+	}
+
+	if err := c.clientQuotas.alterClientQuotas(ctx, entity, nil, remove); err != nil {
+		return errorDiags("Cannot remove the quotas of "+entity.Type, err, "")
+	}
+
+	return nil
+}
+
+func quotaEntityOf(d *schema.ResourceData) quotaEntity {
+	e := quotaEntity{Type: d.Get("entity_type").(string)}
+	if v := d.Get("entity_name").(string); v != "" {
+		e.Name = &v
+	}
+	return e
+}
+
+func quotaValue(d *schema.ResourceData, key string) (float64, bool) {
+	v, ok := d.GetOk(key)
+	if !ok {
+		return 0, false
+	}
+	if i, isInt := v.(int); isInt {
+		return float64(i), true
+	}
+	return v.(float64), true
+}
+
+func quotaId(e quotaEntity) string {
+	if e.Name == nil {
+		return buildId(e.Type, defaultQuotaEntity)
+	}
+	return buildId(e.Type, *e.Name)
+}
+
+func parseQuotaId(id string) (quotaEntity, error) {
+	p, err := parseId("quota", id, "entity_type", "entity_name or "+defaultQuotaEntity)
+	if err != nil {
+		return quotaEntity{}, err
+	}
+	if !contains(validQuotaEntityType, p[0]) {
+		return quotaEntity{}, fmt.Errorf("invalid quota ID %q, the entity type must be one of %s", id, strings.Join(validQuotaEntityType, ", "))
+	}
+
+	e := quotaEntity{Type: p[0]}
+	if p[1] != defaultQuotaEntity {
+		e.Name = &p[1]
+	}
+	return e, nil
+}
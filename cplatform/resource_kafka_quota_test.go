@@ -0,0 +1,115 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaQuota_basic(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: k.providerFactories(t),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			k.testAccCheckQuotas("user|alice", nil),
+			k.testAccCheckQuotas("client-id|<default>", nil),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaQuotaConfig(`
+  consumer_byte_rate = 2097152
+`),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckQuotas("user|alice", map[string]float64{"producer_byte_rate": 1048576, "consumer_byte_rate": 2097152}),
+					k.testAccCheckQuotas("client-id|<default>", map[string]float64{"producer_byte_rate": 524288}),
+					resource.TestCheckResourceAttr("kafka_quota.alice", "id", "user|alice"),
+					resource.TestCheckResourceAttr("kafka_quota.clients", "id", "client-id|<default>"),
+				),
+			},
+			// the removed quota is removed from Kafka
+			{
+				Config: testAccKafkaQuotaConfig(`
+  request_percentage = 50
+`),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckQuotas("user|alice", map[string]float64{"producer_byte_rate": 1048576, "request_percentage": 50}),
+					resource.TestCheckResourceAttr("kafka_quota.alice", "request_percentage", "50"),
+				),
+			},
+			{
+				ResourceName:      "kafka_quota.alice",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "kafka_quota.clients",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccCheckQuotas checks the quotas of the entity, nil when it must have none
+func (k *fakeKafka) testAccCheckQuotas(id string, quotas map[string]float64) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+
+		actual := k.quotas[id]
+		if len(actual) == 0 && len(quotas) == 0 {
+			return nil
+		}
+		if !reflect.DeepEqual(actual, quotas) {
+			return fmt.Errorf("quotas of %s are %v, expected %v", id, actual, quotas)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaQuotaConfig(quotas string) string {
+	return fakeProviderConfig + `
+resource "kafka_quota" "alice" {
+  entity_type        = "user"
+  entity_name        = "alice"
+  producer_byte_rate = 1048576
+` + quotas + `
+  provider = confluent
+}
+
+resource "kafka_quota" "clients" {
+  entity_type        = "client-id"
+  producer_byte_rate = 524288
+
+  provider = confluent
+}
+`
+}
+
+func TestKafkaQuota_keysOfEntityType(t *testing.T) {
+	cases := []struct {
+		config map[string]interface{}
+		err    string
+	}{
+		{map[string]interface{}{"entity_type": "user", "producer_byte_rate": 1024, "request_percentage": 50}, ""},
+		{map[string]interface{}{"entity_type": "ip", "entity_name": "10.0.0.1", "connection_creation_rate": 10}, ""},
+		{map[string]interface{}{"entity_type": "client-id", "connection_creation_rate": 10}, "connection_creation_rate can only be set for the ip entity"},
+		{map[string]interface{}{"entity_type": "ip", "connection_creation_rate": 10, "consumer_byte_rate": 1024}, "consumer_byte_rate cannot be set for the ip entity"},
+	}
+	for _, tc := range cases {
+		_, err := kafkaQuota().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), &Client{})
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%v: %v", tc.config, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%v: got %v, expected %q", tc.config, err, tc.err)
+		}
+	}
+}
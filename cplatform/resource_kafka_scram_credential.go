@@ -0,0 +1,231 @@
+package cplatform
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// errResourceNotFound is returned by Kafka for the users without any SCRAM credential,
+	// sarama v1.29 does not define it
+	errResourceNotFound sarama.KError = 91
+
+	// writeOnlyPassword is the value of the password in the plan and in the state
+	writeOnlyPassword = "(write-only)"
+)
+
+var (
+	scramMechanisms = map[string]sarama.ScramMechanismType{
+		sarama.SASLTypeSCRAMSHA256: sarama.SCRAM_MECHANISM_SHA_256,
+		sarama.SASLTypeSCRAMSHA512: sarama.SCRAM_MECHANISM_SHA_512,
+	}
+)
+
+// kafkaScramCredential manages the SCRAM credential of a user for one mechanism
+// example:
+//
+//	resource "kafka_scram_credential" "app" {
+//	  username   = "app"
+//	  mechanism  = "SCRAM-SHA-512"
+//	  iterations = 8192
+//	  password   = var.app_password
+//	  password_version = 2
+//
+//	  provider = confluent-kafka.confluent
+//	}
+//
+// The password is write-only: neither the plan nor the state has it, so a new password is only set when
+// password_version or iterations change, which replaces the credential.
+// Resource ID = username|mechanism
+func kafkaScramCredential() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: kafkaScramCredentialUpsert,
+		ReadContext:   kafkaScramCredentialRead,
+		UpdateContext: kafkaScramCredentialUpsert,
+		DeleteContext: kafkaScramCredentialDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: resourceIdVersion,
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"mechanism": {
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Required:     true,
+				Description:  "SCRAM-SHA-256 or SCRAM-SHA-512",
+				ValidateFunc: validation.StringInSlice([]string{sarama.SASLTypeSCRAMSHA256, sarama.SASLTypeSCRAMSHA512}, false),
+			},
+			"iterations": {
+				Type:         schema.TypeInt,
+				ForceNew:     true,
+				Optional:     true,
+				Default:      4096,
+				ValidateFunc: validation.IntBetween(4096, 16384),
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The password is never sent to Kafka, only the salted password of SCRAM is. It is not kept in the state, change password_version to set a new one",
+				StateFunc: func(interface{}) string {
+					return writeOnlyPassword
+				},
+			},
+			"password_version": {
+				Type:        schema.TypeInt,
+				ForceNew:    true,
+				Optional:    true,
+				Description: "Any change sets the password of the configuration again",
+			},
+		},
+	}
+}
+
+func kafkaScramCredentialRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	admin, err := meta.(*Client).kafkaAdmin()
+	if err != nil {
+		return errorDiags("Cannot connect to Kafka", err, "")
+	}
+
+	username, mechanism, err := parseScramCredentialId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	results, err := admin.DescribeUserScramCredentials([]string{username})
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot describe the SCRAM credentials", map[string]interface{}{"username": username, "error": err.Error()})
+		return errorDiags("Cannot describe the SCRAM credentials of "+username, err, "username")
+	}
+
+	var found bool
+	for _, r := range results {
+		if r.ErrorCode == errResourceNotFound {
+			continue
+		}
+		if err := kafkaError(r.ErrorCode, r.ErrorMessage); err != nil {
+			return errorDiags("Cannot describe the SCRAM credentials of "+username, err, "username")
+		}
+		for _, info := range r.CredentialInfos {
+			if info.Mechanism != scramMechanisms[mechanism] {
+				continue
+			}
+			found = true
+			if err := d.Set("iterations", int(info.Iterations)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+	if !found {
+		tflog.SubsystemWarn(ctx, logKafka, "The SCRAM credential has been removed, re-create it", map[string]interface{}{"username": username, "mechanism": mechanism})
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("username", username); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mechanism", mechanism); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func kafkaScramCredentialUpsert(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	admin, err := meta.(*Client).kafkaAdmin()
+	if err != nil {
+		return errorDiags("Cannot connect to Kafka", err, "")
+	}
+
+	username := d.Get("username").(string)
+	mechanism := d.Get("mechanism").(string)
+
+	// the value of the configuration, the plan only has writeOnlyPassword
+	password := d.Get("password").(string)
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Setting the SCRAM credential", map[string]interface{}{"username": username, "mechanism": mechanism})
+	results, err := admin.UpsertUserScramCredentials([]sarama.AlterUserScramCredentialsUpsert{
+		{
+			Name:       username,
+			Mechanism:  scramMechanisms[mechanism],
+			Iterations: int32(d.Get("iterations").(int)),
+			Salt:       salt,
+			Password:   []byte(password),
+		},
+	})
+	if err != nil {
+		return errorDiags("Cannot set the SCRAM credential of "+username, err, "username")
+	}
+	for _, r := range results {
+		if err := kafkaError(r.ErrorCode, r.ErrorMessage); err != nil {
+			return errorDiags("Cannot set the SCRAM credential of "+username, err, "password")
+		}
+	}
+
+	d.SetId(buildId(username, mechanism))
+	if err := d.Set("password", writeOnlyPassword); err != nil {
+		return diag.FromErr(err)
+	}
+	return kafkaScramCredentialRead(ctx, d, meta)
+}
+
+func kafkaScramCredentialDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	admin, err := meta.(*Client).kafkaAdmin()
+	if err != nil {
+		return errorDiags("Cannot connect to Kafka", err, "")
+	}
+
+	username := d.Get("username").(string)
+	mechanism := d.Get("mechanism").(string)
+
+	results, err := admin.DeleteUserScramCredentials([]sarama.AlterUserScramCredentialsDelete{
+		{
+			Name:      username,
+			Mechanism: scramMechanisms[mechanism],
+		},
+	})
+	if err != nil {
+		return errorDiags("Cannot delete the SCRAM credential of "+username, err, "username")
+	}
+	for _, r := range results {
+		if err := kafkaError(r.ErrorCode, r.ErrorMessage); err != nil {
+			return errorDiags("Cannot delete the SCRAM credential of "+username, err, "username")
+		}
+	}
+
+	return nil
+}
+
+func parseScramCredentialId(id string) (string, string, error) {
+	p, err := parseId("SCRAM credential", id, "username", "mechanism")
+	if err != nil {
+		return "", "", err
+	}
+	if p[0] == "" {
+		return "", "", fmt.Errorf("invalid SCRAM credential ID %q, the username cannot be empty", id)
+	}
+	if _, ok := scramMechanisms[p[1]]; !ok {
+		return "", "", fmt.Errorf("invalid SCRAM credential ID %q, the mechanism must be %s or %s", id, sarama.SASLTypeSCRAMSHA256, sarama.SASLTypeSCRAMSHA512)
+	}
+	return p[0], p[1], nil
+}
@@ -0,0 +1,92 @@
+package cplatform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaScramCredential_basic(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: k.providerFactories(t),
+		CheckDestroy:      k.testAccCheckScramCredential("alice", sarama.SCRAM_MECHANISM_SHA_512, false, 0, ""),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaScramCredentialConfig(8192, "alice-secret", 1),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckScramCredential("alice", sarama.SCRAM_MECHANISM_SHA_512, true, 8192, "alice-secret"),
+					resource.TestCheckResourceAttr("kafka_scram_credential.alice", "id", "alice|SCRAM-SHA-512"),
+					resource.TestCheckResourceAttr("kafka_scram_credential.alice", "password", writeOnlyPassword),
+				),
+			},
+			// the password is write-only, a new password alone is not a change
+			{
+				Config:   testAccKafkaScramCredentialConfig(8192, "alice-new-secret", 1),
+				PlanOnly: true,
+			},
+			// password_version is ForceNew, the password of the configuration is set
+			{
+				Config: testAccKafkaScramCredentialConfig(8192, "alice-new-secret", 2),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckScramCredential("alice", sarama.SCRAM_MECHANISM_SHA_512, true, 8192, "alice-new-secret"),
+				),
+			},
+			// so is iterations
+			{
+				Config: testAccKafkaScramCredentialConfig(4096, "alice-new-secret", 2),
+				Check: resource.ComposeTestCheckFunc(
+					k.testAccCheckScramCredential("alice", sarama.SCRAM_MECHANISM_SHA_512, true, 4096, "alice-new-secret"),
+					resource.TestCheckResourceAttr("kafka_scram_credential.alice", "iterations", "4096"),
+				),
+			},
+			// Kafka does not return the password
+			{
+				ResourceName:            "kafka_scram_credential.alice",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "password_version"},
+			},
+		},
+	})
+}
+
+func (k *fakeKafka) testAccCheckScramCredential(user string, mechanism sarama.ScramMechanismType, exists bool, iterations int32, password string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+
+		credential, ok := k.scram[user][mechanism]
+		if ok != exists {
+			return fmt.Errorf("SCRAM credential %s of %s: exists should be %t", mechanism, user, exists)
+		}
+		if !exists {
+			return nil
+		}
+		if credential.Iterations != iterations || string(credential.Password) != password {
+			return fmt.Errorf("SCRAM credential %s of %s has %d iterations and password %q, expected %d and %q",
+				mechanism, user, credential.Iterations, credential.Password, iterations, password)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaScramCredentialConfig(iterations int, password string, version int) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "kafka_scram_credential" "alice" {
+  username   = "alice"
+  mechanism  = "SCRAM-SHA-512"
+  iterations = %d
+  password   = %q
+
+  password_version = %d
+
+  provider = confluent
+}
+`, iterations, password, version)
+}
@@ -1,13 +1,7 @@
 package cplatform
 
 import (
-	"context"
-	"fmt"
-	"log"
-	"strings"
-
-	confluent "github.com/OneMount/gonfluent"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -24,34 +18,31 @@ var err error
 //
 //   provider = confluent-kafka.confluent
 //	}
-// Resource ID = principal + "|" + clusterId + "|" + principal + "|" + role
+// Resource ID = cluster_id|principal|role|resource_type|name|pattern_type
 func kafkaTopicRBAC() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: kafkaTopicRBACCreate,
-		DeleteContext: kafkaTopicRBACDelete,
-		ReadContext:   kafkaTopicRBACRead,
+		CreateContext: resourcePatternCreate(""),
+		DeleteContext: resourcePatternDelete(""),
+		ReadContext:   resourcePatternRead(""),
+		CustomizeDiff: customdiff.Sequence(
+			clusterIdsCustomizeDiff(nil),
+			roleScopeCustomizeDiff(func(d *schema.ResourceDiff) (string, string, bool) {
+				return "Kafka", d.Get("resource_type").(string), d.NewValueKnown("resource_type")
+			}),
+		),
+		Importer:      importIdAttributes("Kafka topic RBAC", "cluster_id", "principal", "role", "resource_type", "name", "pattern_type"),
+
+		SchemaVersion:  resourceIdVersion,
+		StateUpgraders: []schema.StateUpgrader{idStateUpgrader(schemaV0("principal", "role", "resource_type", "pattern_type", "name", "cluster_id"), upgradeKafkaTopicRBACId)},
 
 		Schema: map[string]*schema.Schema{
-			"principal": {
+			"principal": principalSchema(),
+			"role": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
 				Required:    true,
-				Description: "Defined the principal - User or subject",
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(string)
-					if !strings.Contains(v, "User:") || !strings.Contains(v, "Group:") && strings.Contains(v, "|") {
-						errs = append(errs, fmt.Errorf("%q must be defined with User: or Group: and must not have |, got: %s", key, v))
-					}
-					return
-				},
-			},
-			"role": {
-				Type:         schema.TypeString,
-				ForceNew:     true,
-				Required:     true,
-				Description:  "Role of",
-				DefaultFunc:  schema.EnvDefaultFunc("ROLE", "DeveloperRead"),
-				ValidateFunc: validation.StringInSlice(scopeRole, false),
+				Description: "Role of",
+				DefaultFunc: schema.EnvDefaultFunc("ROLE", "DeveloperRead"),
 			},
 			"resource_type": {
 				Type:        schema.TypeString,
@@ -75,108 +66,14 @@ func kafkaTopicRBAC() *schema.Resource {
 				ForceNew:    true,
 				Optional:    true,
 				Computed:    true,
-				Description: "The ID of cluster",
-			},
-		},
-	}
-}
-
-func kafkaTopicRBACRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	principal := d.Get("principal").(string)
-	role := d.Get("role").(string)
-	clusterId := d.Get("cluster_id").(string)
-
-	cDetails := &confluent.ClusterDetails{}
-	cDetails.Clusters.KafkaCluster = clusterId
-
-	roleBindings, err := c.LookupRoleBinding(principal, role, *cDetails)
-	if err != nil {
-		log.Printf("[ERROR] Error lookup role-binding %s from Confluent", err)
-		return diag.FromErr(err)
-	}
-
-	r := make(map[string]string)
-
-	for _, v := range roleBindings {
-		if v.Name == d.Get("name").(string) {
-			r[v.ResourceType] = v.PatternType
-		}
-	}
-
-	if r[d.Get("resource_type").(string)] != d.Get("pattern_type").(string) {
-		err = fmt.Errorf("cannot find resource_type" + d.Get("resource_type").(string) + " of" + d.Get("name").(string))
-		log.Printf("[ERROR] Error lookup role-binding from Confluent %s", err)
-		return diag.FromErr(err)
-	}
-	return nil
-}
-
-func kafkaTopicRBACCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	if err := kafkaTopicRBACRead(nil, d, meta); err == nil {
-		msg := fmt.Errorf("cannot create resource existed resource_type" + d.Get("resource_type").(string) + " of" + d.Get("name").(string))
-		log.Printf("[ERROR] Resource existed when create %s", msg)
-		return diag.FromErr(msg)
-	}
-	c := meta.(*confluent.Client)
-	principal := d.Get("principal").(string)
-	role := d.Get("role").(string)
-	clusterId := d.Get("cluster_id").(string)
-
-	cDetails := &confluent.ClusterDetails{
-		Clusters: confluent.Clusters{
-			KafkaCluster: clusterId,
-		},
-	}
-
-	u := confluent.RoleBinding{
-		Scope: *cDetails,
-		ResourcePatterns: []confluent.ResourcePattern{
-			{
-				ResourceType: d.Get("resource_type").(string),
-				Name:         d.Get("name").(string),
-				PatternType:  d.Get("pattern_type").(string),
+				Description: "The ID of cluster, or an alias of the provider clusters. The default_kafka_cluster_id of the provider when it is not set",
 			},
 		},
 	}
-
-	err = c.IncreaseRoleBinding(principal, role, u)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	rId := clusterId + "|" + principal + "|" + role + "|" + d.Get("resource_type").(string) + "|" + d.Get("name").(string) + "|" + d.Get("pattern_type").(string)
-	d.SetId(rId)
-	return nil
 }
 
-func kafkaTopicRBACDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	principal := d.Get("principal").(string)
-	role := d.Get("role").(string)
-	clusterId := d.Get("cluster_id").(string)
-
-	cDetails := &confluent.ClusterDetails{
-		Clusters: confluent.Clusters{
-			KafkaCluster: clusterId,
-		},
-	}
-
-	u := confluent.RoleBinding{
-		Scope: *cDetails,
-		ResourcePatterns: []confluent.ResourcePattern{
-			{
-				ResourceType: d.Get("resource_type").(string),
-				Name:         d.Get("name").(string),
-				PatternType:  d.Get("pattern_type").(string),
-			},
-		},
-	}
-
-	err = c.DecreaseRoleBinding(principal, role, u)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	return nil
+// upgradeKafkaTopicRBACId escapes the fields of the ID, its layout is the same
+func upgradeKafkaTopicRBACId(rawState map[string]interface{}) string {
+	return buildId(stateString(rawState, "cluster_id"), normalizePrincipal(stateString(rawState, "principal")), stateString(rawState, "role"),
+		stateString(rawState, "resource_type"), stateString(rawState, "name"), stateString(rawState, "pattern_type"))
 }
@@ -0,0 +1,146 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaTopicRBAC_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	kafka := confluent.ClusterDetails{}
+	kafka.Clusters.KafkaCluster = fakeClusterId
+	payments := confluent.ResourcePattern{ResourceType: "Topic", Name: "payments-", PatternType: "PREFIXED"}
+	orders := confluent.ResourcePattern{ResourceType: "Topic", Name: "orders", PatternType: "LITERAL"}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", kafka, payments, false),
+			f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", kafka, orders, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaTopicRBACConfig("payments-", "PREFIXED"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", kafka, payments, true),
+					resource.TestCheckResourceAttr("kafka_topic_rbac.alice", "id", fakeClusterId+"|User:alice|DeveloperRead|Topic|payments-|PREFIXED"),
+				),
+			},
+			// every attribute is ForceNew, the binding is replaced
+			{
+				Config: testAccKafkaTopicRBACConfig("orders", "LITERAL"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", kafka, payments, false),
+					f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", kafka, orders, true),
+				),
+			},
+			{
+				ResourceName:      "kafka_topic_rbac.alice",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// the lookup endpoints see the binding
+			{
+				Config: testAccKafkaTopicRBACConfig("orders", "LITERAL") + fmt.Sprintf(`
+data "rbac_principal_bindings" "alice" {
+  principal  = "User:alice"
+  cluster_id = %q
+
+  provider = confluent
+}
+`, fakeClusterId),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.rbac_principal_bindings.alice", "roles.#", "1"),
+					resource.TestCheckResourceAttr("data.rbac_principal_bindings.alice", "roles.0", "DeveloperRead"),
+					resource.TestCheckResourceAttr("data.rbac_principal_bindings.alice", "bindings.0.name", "orders"),
+				),
+			},
+		},
+	})
+}
+
+func TestKafkaTopicRBACRead_removed(t *testing.T) {
+	f := newFakeConfluent(t)
+	c, err := f.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := kafkaTopicRBAC()
+	attributes := map[string]interface{}{
+		"principal":     "User:alice",
+		"role":          "DeveloperRead",
+		"resource_type": "Topic",
+		"name":          "payments",
+		"pattern_type":  "LITERAL",
+		"cluster_id":    fakeClusterId,
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, attributes)
+	if diags := r.CreateContext(context.Background(), d, c); len(diags) > 0 {
+		t.Fatalf("create: %v", diags)
+	}
+
+	// creating it again fails, the binding has to be imported
+	again := schema.TestResourceDataRaw(t, r.Schema, attributes)
+	if diags := r.CreateContext(context.Background(), again, c); !diags.HasError() || !strings.Contains(diags[0].Detail, d.Id()) {
+		t.Errorf("expected an error with the ID to import creating the binding again, got %v", diags)
+	}
+	if again.Id() != "" {
+		t.Errorf("the ID is %s, expected the binding not to be adopted", again.Id())
+	}
+
+	// a state of the previous versions has the alias, reading it sets the ID of the resource ID
+	if err := d.Set("cluster_id", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if id := d.Get("cluster_id"); id != fakeClusterId {
+		t.Errorf("cluster_id is %v after the read, want %s", id, fakeClusterId)
+	}
+
+	// the binding is removed out of band, terraform plans to create it again
+	for k := range f.bindings {
+		delete(f.bindings, k)
+	}
+	if diags := r.ReadContext(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("the ID is %s, expected it to be cleared", d.Id())
+	}
+}
+
+func (f *fakeConfluent) testAccCheckResourceRoleBinding(principal, role string, cd confluent.ClusterDetails, pattern confluent.ResourcePattern, exists bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if f.hasBinding(principal, role, cd, &pattern) != exists {
+			return fmt.Errorf("role binding %s of %s on %s %s at %s: exists should be %t", role, principal, pattern.ResourceType, pattern.Name, scopeKey(cd), exists)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaTopicRBACConfig(name, patternType string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "kafka_topic_rbac" "alice" {
+  principal     = "User:alice"
+  role          = "DeveloperRead"
+  resource_type = "Topic"
+  name          = %q
+  pattern_type  = %q
+  cluster_id    = %q
+
+  provider = confluent
+}
+`, name, patternType, fakeClusterId)
+}
@@ -0,0 +1,109 @@
+package cplatform
+
+import (
+	"fmt"
+	"testing"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSchemaRegistryRBAC_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	sr := confluent.ClusterDetails{}
+	sr.Clusters.KafkaCluster = fakeClusterId
+	sr.Clusters.SchemaRegistryCluster = "schema-registry"
+	subjects := confluent.ResourcePattern{ResourceType: "Subject", Name: "payments-", PatternType: "PREFIXED"}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", sr, subjects, false),
+			f.testAccCheckResourceRoleBinding("User:alice", "DeveloperWrite", sr, subjects, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaRegistryRBACConfig("DeveloperRead"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", sr, subjects, true),
+				),
+			},
+			// role is ForceNew, the binding is replaced
+			{
+				Config: testAccSchemaRegistryRBACConfig("DeveloperWrite"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "DeveloperRead", sr, subjects, false),
+					f.testAccCheckResourceRoleBinding("User:alice", "DeveloperWrite", sr, subjects, true),
+				),
+			},
+			{
+				ResourceName:      "schema_registry_rbac.alice",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// SubjectReader is a custom role of the fake MDS, it is only known with refresh_roles
+func TestAccSchemaRegistryRBAC_customRole(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	sr := confluent.ClusterDetails{}
+	sr.Clusters.KafkaCluster = fakeClusterId
+	sr.Clusters.SchemaRegistryCluster = "schema-registry"
+	subjects := confluent.ResourcePattern{ResourceType: "Subject", Name: "payments-", PatternType: "PREFIXED"}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy:      f.testAccCheckResourceRoleBinding("User:alice", "SubjectReader", sr, subjects, false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSchemaRegistryRBACCustomRoleConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckResourceRoleBinding("User:alice", "SubjectReader", sr, subjects, true),
+				),
+			},
+		},
+	})
+}
+
+func testAccSchemaRegistryRBACConfig(role string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "schema_registry_rbac" "alice" {
+  principal                  = "User:alice"
+  role                       = %q
+  name                       = "payments-"
+  pattern_type               = "PREFIXED"
+  cluster_id                 = %q
+  schema_registry_cluster_id = "schema-registry"
+
+  provider = confluent
+}
+`, role, fakeClusterId)
+}
+
+func testAccSchemaRegistryRBACCustomRoleConfig() string {
+	return fmt.Sprintf(`
+provider "confluent" {
+  username          = "admin"
+  password          = "admin-secret"
+  bootstrap_servers = ["localhost:9093"]
+  refresh_roles     = true
+}
+
+resource "schema_registry_rbac" "alice" {
+  principal                  = "User:alice"
+  role                       = "SubjectReader"
+  name                       = "payments-"
+  pattern_type               = "PREFIXED"
+  cluster_id                 = %q
+  schema_registry_cluster_id = "schema-registry"
+
+  provider = confluent
+}
+`, fakeClusterId)
+}
@@ -3,11 +3,12 @@ package cplatform
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	confluent "github.com/OneMount/gonfluent"
@@ -19,12 +20,19 @@ func topics() *schema.Resource {
 		DeleteContext: topicsDelete,
 		ReadContext:   topicsRead,
 		UpdateContext: topicsUpdate,
+		CustomizeDiff: customdiff.Sequence(clusterIdsCustomizeDiff(nil), topicsRenameCustomizeDiff, topicsCustomizeDiff, topicsBackendCustomizeDiff),
+		Importer: &schema.ResourceImporter{
+			StateContext: topicsImport,
+		},
+
+		SchemaVersion:  resourceIdVersion,
+		StateUpgraders: []schema.StateUpgrader{topicStateUpgrader()},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				ForceNew: true,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Changing the name replaces the topic, unless copy_on_rename is true",
 			},
 			"partitions": {
 				Type:        schema.TypeInt,
@@ -48,52 +56,186 @@ func topics() *schema.Resource {
 			"cluster_id": {
 				Type:        schema.TypeString,
 				ForceNew:    true,
-				Required:    true,
-				Description: "The ID of cluster",
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of cluster, or an alias of the provider clusters. The default_kafka_cluster_id of the provider when it is not set",
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail the deletion or the replacement of the topic",
+			},
+			"deletion_check_consumers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail the deletion of the topic while consumer groups have committed offsets on it",
+			},
+			"copy_on_rename": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Rename the topic by copying its messages to a new topic, the old topic is deleted once the copy is verified",
+			},
+			"copy_consumer_offsets": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Also copy the offsets committed by the consumer groups when the topic is renamed",
 			},
 		},
 	}
 }
 
-func topicsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	clusterId := d.Get("cluster_id").(string)
-	topicName := d.Id()
-	//topicName := d.Get("topic_name").(string)
+// topicsImport imports cluster_id|name, or the name of a topic of default_kafka_cluster_id.
+// The configs set on the topic become its config.
+func topicsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*Client)
+	clusterId, topicName := "", d.Id()
+	if strings.Contains(d.Id(), resourceIdSeparator) {
+		p, err := parseId("topic", d.Id(), "cluster_id", "name")
+		if err != nil {
+			return nil, err
+		}
+		clusterId, topicName = p[0], p[1]
+	}
+	clusterId, err := c.resolveClusterId("cluster_id", clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if clusterId == "" {
+		return nil, fmt.Errorf("invalid topic ID %q, expected <cluster_id>|<name>, or <name> when the provider has a default_kafka_cluster_id", d.Id())
+	}
 
-	topic, err := c.GetTopic(clusterId, topicName)
+	topic, err := c.topics.GetTopic(clusterId, topicName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the topic %s: %w", topicName, err)
+	}
+	tflog.SubsystemInfo(ctx, logKafka, "Importing the topic", map[string]interface{}{"cluster_id": clusterId, "topic": topicName})
+
+	config := make(map[string]interface{})
+	for _, v := range topic.Config {
+		if v.Source == "DYNAMIC_TOPIC_CONFIG" {
+			config[v.Name] = v.Value
+		}
+	}
+	attributes := map[string]interface{}{
+		"cluster_id":               clusterId,
+		"name":                     topic.Name,
+		"partitions":               int(topic.Partitions),
+		"replication_factor":       int(topic.ReplicationFactor),
+		"config":                   config,
+		"deletion_protection":      false,
+		"deletion_check_consumers": false,
+		"copy_on_rename":           false,
+		"copy_consumer_offsets":    false,
+	}
+	for k, v := range attributes {
+		if err := d.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+	d.SetId(buildId(clusterId, topic.Name))
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseTopicId returns the cluster ID and the name of the topic of a resource ID
+func parseTopicId(id string) (string, string, error) {
+	p, err := parseId("topic", id, "cluster_id", "name")
+	if err != nil {
+		return "", "", err
+	}
+	return p[0], p[1], nil
+}
+
+// topicSchemaV0 is the frozen schema of version 0 of kafka_topic, whose ID was the name of the topic
+func topicSchemaV0() map[string]*schema.Schema {
+	s := schemaV0("name", "cluster_id")
+	s["partitions"] = &schema.Schema{Type: schema.TypeInt, Optional: true}
+	s["replication_factor"] = &schema.Schema{Type: schema.TypeInt, Optional: true}
+	s["config"] = &schema.Schema{Type: schema.TypeMap, Optional: true, Elem: schema.TypeString}
+	for _, k := range []string{"deletion_protection", "deletion_check_consumers", "copy_on_rename", "copy_consumer_offsets"} {
+		s[k] = &schema.Schema{Type: schema.TypeBool, Optional: true}
+	}
+	return s
+}
+
+// topicStateUpgrader upgrades the IDs of version 0 to cluster_id|name. The cluster_id of version 0 can be an alias of
+// the provider clusters, it is resolved first.
+func topicStateUpgrader() schema.StateUpgrader {
+	u := idStateUpgrader(topicSchemaV0(), func(rawState map[string]interface{}) string {
+		return buildId(stateString(rawState, "cluster_id"), stateString(rawState, "id"))
+	})
+	upgrade := u.Upgrade
+	u.Upgrade = func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		if c, ok := meta.(*Client); ok && rawState != nil {
+			clusterId, err := c.resolveClusterId("cluster_id", stateString(rawState, "cluster_id"))
+			if err != nil {
+				return nil, err
+			}
+			rawState["cluster_id"] = clusterId
+		}
+		return upgrade(ctx, rawState, meta)
+	}
+	return u
+}
+
+func topicsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).topics
+	clusterId, topicName, err := parseTopicId(d.Id())
 	if err != nil {
-		log.Printf("[ERROR] Error getting topics %s from Confluent", err)
-		d.SetId("")
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[DEBUG] Setting the state from Confluent %v", topic)
+	topic, err := c.GetTopic(clusterId, topicName)
+	if err != nil && isTopicNotFound(err) {
+		tflog.SubsystemWarn(ctx, logKafka, "The topic has been removed, re-create it", map[string]interface{}{"cluster_id": clusterId, "topic": topicName})
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		tflog.SubsystemError(ctx, logKafka, "Cannot read the topic", map[string]interface{}{"cluster_id": clusterId, "topic": topicName, "error": err.Error()})
+		return topicErrorDiags("Cannot read the topic "+topicName, err, "name")
+	}
+
+	tflog.SubsystemDebug(ctx, logKafka, "Setting the state of the topic", map[string]interface{}{
+		"cluster_id":         clusterId,
+		"topic":              topic.Name,
+		"partitions":         topic.Partitions,
+		"replication_factor": topic.ReplicationFactor,
+	})
 	err = d.Set("name", topic.Name)
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// a state written before the aliases were resolved has the alias
+	err = d.Set("cluster_id", clusterId)
 	return diag.FromErr(err)
 }
 
-func topicsCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Printf("[INFO] Creating topic" + d.Get("name").(string))
-	c := meta.(*confluent.Client)
+func topicsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Client).topics
 	topicName := d.Get("name").(string)
+	ids, diags := meta.(*Client).setClusterIds(d, nil)
+	if diags != nil {
+		return diags
+	}
+	clusterId := ids["cluster_id"]
+	fields := map[string]interface{}{"cluster_id": clusterId, "topic": topicName}
+	tflog.SubsystemInfo(ctx, logKafka, "Creating the topic", fields)
 
-	if err := topicCreateFunc(c, d); err != nil {
-		return diag.FromErr(err)
+	if err := topicCreateFunc(c, clusterId, d); err != nil {
+		return topicErrorDiags("Cannot create the topic "+topicName, err, "name")
 	}
-	log.Printf("[INFO] Creat topic" + d.Get("name").(string) + "successfully!")
-	d.SetId(topicName)
+	tflog.SubsystemInfo(ctx, logKafka, "Created the topic", fields)
 
 	return diags
 }
 
-func topicCreateFunc(c *confluent.Client, d *schema.ResourceData) error {
-	clusterId:= d.Get("cluster_id").(string)
+func topicCreateFunc(c topicAPI, clusterId string, d *schema.ResourceData) error {
 	topicName := d.Get("name").(string)
 	partitionsCount := d.Get("partitions").(int)
 	replicationFactor := d.Get("replication_factor").(int)
@@ -119,36 +261,62 @@ func topicCreateFunc(c *confluent.Client, d *schema.ResourceData) error {
 	if err != nil {
 		return err
 	}
-	d.SetId(topicName)
+	d.SetId(buildId(clusterId, topicName))
 	return nil
 }
 
 func topicsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Printf("[INFO] Delete topic" + d.Get("name").(string))
-	c := meta.(*confluent.Client)
+	clusterId, topicName, err := parseTopicId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if protection := topicDeletionDiags(meta.(*Client), topicName, d.Get("deletion_protection").(bool), d.Get("deletion_check_consumers").(bool)); protection.HasError() {
+		return protection
+	}
+	c := meta.(*Client).topics
 
-	clusterId:= d.Get("cluster_id").(string)
-	topicName := d.Id()
+	fields := map[string]interface{}{"cluster_id": clusterId, "topic": topicName}
+	tflog.SubsystemInfo(ctx, logKafka, "Deleting the topic", fields)
 
 	if err := c.DeleteTopic(clusterId, topicName); err != nil {
-		return diag.FromErr(err)
+		return topicErrorDiags("Cannot delete the topic "+topicName, err, "name")
 	}
 
-	if err := waitForTopicDelete(ctx, c, d.Id(), clusterId); err != nil {
-		return diag.FromErr(err)
+	if err := waitForTopicDelete(ctx, c, topicName, clusterId); err != nil {
+		return topicErrorDiags("Cannot delete the topic "+topicName, err, "name")
 	}
-	log.Printf("[INFO] Topic" + d.Get("name").(string) + " deleted!")
-	return diags
+	tflog.SubsystemInfo(ctx, logKafka, "Deleted the topic", fields)
+	return nil
 }
 
 func topicsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*confluent.Client)
-	clusterId:= d.Get("cluster_id").(string)
+	c := meta.(*Client).topics
+	clusterId, topicName, err := parseTopicId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	t := confluent.Topic{
-		Name: d.Id(),
+		ClusterID: clusterId,
+		Name:      topicName,
+	}
+
+	// the new topic is created with the new partitions and config
+	if d.HasChange("name") {
+		// the old topic is deleted, the consumer groups whose offsets are copied are not its consumers anymore
+		protection, _ := d.GetChange("deletion_protection")
+		checkConsumers := d.Get("deletion_check_consumers").(bool) && !d.Get("copy_consumer_offsets").(bool)
+		if diags := topicDeletionDiags(meta.(*Client), topicName, protection.(bool), checkConsumers); diags.HasError() {
+			return diags
+		}
+		if err := topicRename(ctx, meta.(*Client), d); err != nil {
+			return topicErrorDiags("Cannot rename the topic "+topicName, err, "name")
+		}
+		return nil
+	}
+
+	// deletion_protection, deletion_check_consumers, copy_on_rename and copy_consumer_offsets are only kept in the state
+	if !d.HasChange("replication_factor") && !d.HasChange("partitions") && !d.HasChange("config") {
+		return nil
 	}
 
 	// update replica count of existing partitions before adding new ones
@@ -158,17 +326,22 @@ func topicsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{})
 			oldRF := oi.(int)
 			newRF := ni.(int)
 			t.ReplicationFactor = int16(newRF)
-			log.Printf("[INFO] Updating replication_factor from %d to %d", oldRF, newRF)
+			tflog.SubsystemInfo(ctx, logKafka, "Updating the replication factor of the topic", map[string]interface{}{
+				"cluster_id": clusterId,
+				"topic":      topicName,
+				"old":        oldRF,
+				"new":        newRF,
+			})
 			err := c.UpdateReplicationsFactor(t)
 			if err != nil {
-				return diag.FromErr(err)
+				return topicErrorDiags("Cannot update the replication factor of the topic "+topicName, err, "replication_factor")
 			}
 
-			if err := waitForRFUpdate(ctx, c, d.Id()); err != nil {
-				return diag.FromErr(err)
+			if err := waitForRFUpdate(ctx, c, topicName); err != nil {
+				return topicErrorDiags("Cannot update the replication factor of the topic "+topicName, err, "replication_factor")
 			}
-			if err := waitForTopicRefresh(ctx, c, d.Id(), clusterId, t); err != nil {
-				return diag.FromErr(err)
+			if err := waitForTopicRefresh(ctx, c, topicName, clusterId, t); err != nil {
+				return topicErrorDiags("Cannot update the replication factor of the topic "+topicName, err, "replication_factor")
 			}
 		}
 	}
@@ -179,16 +352,21 @@ func topicsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{})
 		oldPartitions := oi.(int)
 		newPartitions := ni.(int)
 		if newPartitions < oldPartitions {
-			return diag.FromErr(fmt.Errorf("cannot decrease the number of partitions of topic"))
+			return diag.Diagnostics{newDiagnostic(diag.Error, "Cannot decrease the number of partitions of the topic "+topicName, fmt.Sprintf("Kafka cannot remove partitions, the topic has %d partitions. Replace the topic to have less partitions.", oldPartitions), "partitions")}
 		}
-		log.Printf("[INFO] Updating partitions from %d to %d", oldPartitions, newPartitions)
+		tflog.SubsystemInfo(ctx, logKafka, "Updating the partitions of the topic", map[string]interface{}{
+			"cluster_id": clusterId,
+			"topic":      topicName,
+			"old":        oldPartitions,
+			"new":        newPartitions,
+		})
 		t.Partitions = int32(newPartitions)
 
 		if err := c.UpdatePartitions(t); err != nil {
-			return diag.FromErr(err)
+			return topicErrorDiags("Cannot update the partitions of the topic "+topicName, err, "partitions")
 		}
-		if err := waitForTopicRefresh(ctx, c, d.Id(), clusterId, t); err != nil {
-			return diag.FromErr(err)
+		if err := waitForTopicRefresh(ctx, c, topicName, clusterId, t); err != nil {
+			return topicErrorDiags("Cannot update the partitions of the topic "+topicName, err, "partitions")
 		}
 	}
 
@@ -207,12 +385,24 @@ func topicsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{})
 			}
 		}
 
-		if err := c.UpdateTopicConfigs(clusterId, d.Id(),topicConfigs); err != nil {
-			return diag.FromErr(err)
+		if err := c.UpdateTopicConfigs(clusterId, topicName, topicConfigs); err != nil {
+			return topicErrorDiags("Cannot update the config of the topic "+topicName, err, "config")
+		}
+
+		// the configs removed from the resource are reset to the defaults of the brokers
+		o, _ := d.GetChange("config")
+		var removed []string
+		for key := range o.(map[string]interface{}) {
+			if _, ok := config[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+		if err := meta.(*Client).deleteTopicConfigs(clusterId, topicName, removed); err != nil {
+			return topicErrorDiags("Cannot reset the config of the topic "+topicName, err, "config")
 		}
 	}
-	if err := waitForTopicRefresh(ctx, c, d.Id(), clusterId, t); err != nil {
-		return diag.FromErr(err)
+	if err := waitForTopicRefresh(ctx, c, topicName, clusterId, t); err != nil {
+		return topicErrorDiags("Cannot update the topic "+topicName, err, "")
 	}
 
 	return nil
@@ -226,7 +416,7 @@ func confluentPlacementConstraintsIsPresent(d *schema.ResourceData) bool {
 	return false
 }
 
-func waitForRFUpdate(ctx context.Context, c *confluent.Client, topic string) error {
+func waitForRFUpdate(ctx context.Context, c topicAPI, topic string) error {
 	refresh := func() (interface{}, string, error) {
 		isRFUpdating, err := c.IsReplicationFactorUpdating(topic)
 		if err != nil {
@@ -257,11 +447,11 @@ func waitForRFUpdate(ctx context.Context, c *confluent.Client, topic string) err
 	return nil
 }
 
-func waitForTopicDelete(ctx context.Context,c *confluent.Client, topic, clusterId string) error {
+func waitForTopicDelete(ctx context.Context,c topicAPI, topic, clusterId string) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:      []string{"Updating"},
 		Target:       []string{"Ready"},
-		Refresh:      topicDeleteRefreshFunc(c, topic, clusterId),
+		Refresh:      topicDeleteRefreshFunc(ctx, c, topic, clusterId),
 		Timeout:      120 * time.Second,
 		Delay:        1 * time.Second,
 		PollInterval: 1 * time.Second,
@@ -277,11 +467,11 @@ func waitForTopicDelete(ctx context.Context,c *confluent.Client, topic, clusterI
 	return nil
 }
 
-func waitForTopicRefresh(ctx context.Context,c *confluent.Client, topic, clusterId string, expected confluent.Topic) error {
+func waitForTopicRefresh(ctx context.Context,c topicAPI, topic, clusterId string, expected confluent.Topic) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:      []string{"Updating"},
 		Target:       []string{"Ready"},
-		Refresh:      topicRefreshFunc(c, topic, clusterId, expected),
+		Refresh:      topicRefreshFunc(ctx, c, topic, clusterId, expected),
 		Timeout:      120 * time.Second,
 		Delay:        1 * time.Second,
 		PollInterval: 1 * time.Second,
@@ -297,33 +487,30 @@ func waitForTopicRefresh(ctx context.Context,c *confluent.Client, topic, cluster
 	return nil
 }
 
-func topicRefreshFunc(c *confluent.Client, topic, clusterId string, expected confluent.Topic) resource.StateRefreshFunc {
+func topicRefreshFunc(ctx context.Context, c topicAPI, topic, clusterId string, expected confluent.Topic) resource.StateRefreshFunc {
+	fields := map[string]interface{}{"cluster_id": clusterId, "topic": topic}
 	return func() (result interface{}, s string, err error) {
-		log.Printf("[DEBUG] waiting for topic to update %s", topic)
+		tflog.SubsystemDebug(ctx, logKafka, "Waiting for the topic to update", fields)
 		actual, err := c.GetTopic(clusterId, topic)
 		if err != nil {
-			log.Printf("[ERROR] could not read topic %s, %s", topic, err)
+			tflog.SubsystemError(ctx, logKafka, "Cannot read the topic", fields, map[string]interface{}{"error": err.Error()})
 			return actual, "Error", err
 		}
-		p, err  := c.GetTopicPartitions(clusterId, topic)
-		if err != nil {
-			log.Printf("[ERROR] could not read topic %s, %s", topic, err)
-			return actual, "Error", err
-		}
-
-		if int16(actual.ReplicationFactor) == expected.ReplicationFactor && int32(len(p)) == expected.Partitions  {
+		// only the replication factor or the partitions being updated are set in expected
+		if (expected.ReplicationFactor == 0 || actual.ReplicationFactor == expected.ReplicationFactor) &&
+			(expected.Partitions == 0 || actual.Partitions == expected.Partitions) {
 			return actual, "Ready", nil
 		}
 
-		return nil, fmt.Sprintf("%v != %v", "", ""), nil
+		return actual, "Updating", nil
 	}
 }
 
-func topicDeleteRefreshFunc(c *confluent.Client, topic, clusterId string) resource.StateRefreshFunc {
+func topicDeleteRefreshFunc(ctx context.Context, c topicAPI, topic, clusterId string) resource.StateRefreshFunc {
 	return func() (result interface{}, s string, err error) {
-		log.Printf("[DEBUG] waiting for topic to delete %s", topic)
+		tflog.SubsystemDebug(ctx, logKafka, "Waiting for the topic to be deleted", map[string]interface{}{"cluster_id": clusterId, "topic": topic})
 		actual, err := c.GetTopic(clusterId, topic)
-		if err != nil && strings.Contains(err.Error(), "404") {
+		if err != nil && isTopicNotFound(err) {
 			return actual, "Ready", nil
 		}
 
@@ -0,0 +1,292 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccKafkaTopic_basic(t *testing.T) {
+	f := newFakeConfluent(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccFakePreCheck(t) },
+		ProviderFactories: f.providerFactories(),
+		CheckDestroy:      f.testAccCheckTopicDestroy("payments"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaTopicConfig(3, "86400000"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckTopic("payments", 3, "86400000"),
+					resource.TestCheckResourceAttr("kafka_topic.payments", "id", "payments"),
+					resource.TestCheckResourceAttr("kafka_topic.payments", "partitions", "3"),
+				),
+			},
+			{
+				Config: testAccKafkaTopicConfig(6, "172800000"),
+				Check: resource.ComposeTestCheckFunc(
+					f.testAccCheckTopic("payments", 6, "172800000"),
+					resource.TestCheckResourceAttr("kafka_topic.payments", "partitions", "6"),
+					resource.TestCheckResourceAttr("kafka_topic.payments", "config.retention.ms", "172800000"),
+				),
+			},
+			// the ID of a topic is its name, it is imported with its cluster_id
+			{
+				ResourceName:      "kafka_topic.payments",
+				ImportState:       true,
+				ImportStateId:     fakeClusterId + "|payments",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func (f *fakeConfluent) testAccCheckTopic(name string, partitions int, retention string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		topic, ok := f.topic(name)
+		if !ok {
+			return fmt.Errorf("topic %s does not exist", name)
+		}
+		if topic.partitions != partitions {
+			return fmt.Errorf("topic %s has %d partitions, expected %d", name, topic.partitions, partitions)
+		}
+		if v := topic.configs["retention.ms"]; v != retention {
+			return fmt.Errorf("topic %s has retention.ms %q, expected %q", name, v, retention)
+		}
+		return nil
+	}
+}
+
+func (f *fakeConfluent) testAccCheckTopicDestroy(name string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if _, ok := f.topic(name); ok {
+			return fmt.Errorf("topic %s still exists", name)
+		}
+		return nil
+	}
+}
+
+func testAccKafkaTopicConfig(partitions int, retention string) string {
+	return fakeProviderConfig + fmt.Sprintf(`
+resource "kafka_topic" "payments" {
+  cluster_id         = %q
+  name               = "payments"
+  partitions         = %d
+  replication_factor = 3
+  config = {
+    "retention.ms" = %q
+  }
+
+  provider = confluent
+}
+`, fakeClusterId, partitions, retention)
+}
+
+func TestTopicsUpdate_partitions(t *testing.T) {
+	rest := newFakeConfluent(t)
+	k := newFakeKafka(t, rest)
+	k.createTopic("payments", 3, 2, map[string]string{"retention.ms": "86400000"})
+
+	state, diags := applyTopic(t, k.client(t, ""), testTopicAttributes(3, 2, "86400000"), testTopicAttributes(6, 2, "172800000"))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if v := state.Attributes["partitions"]; v != "6" {
+		t.Errorf("partitions is %s in the state, expected 6", v)
+	}
+	if n := k.requests(isCreatePartitions); n != 1 {
+		t.Errorf("%d CreatePartitions sent to the controller, expected 1", n)
+	}
+	if topic, _ := k.topic("payments"); len(topic.replicas) != 6 {
+		t.Errorf("topic has %d partitions, expected 6", len(topic.replicas))
+	}
+	if topic, _ := rest.topic("payments"); topic.configs["retention.ms"] != "172800000" {
+		t.Errorf("retention.ms is %s, expected 172800000", topic.configs["retention.ms"])
+	}
+}
+
+func TestTopicsUpdate_replicationFactor(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+
+	_, diags := applyTopic(t, k.client(t, ""), testTopicAttributes(3, 2, ""), testTopicAttributes(3, 3, ""))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	topic, _ := k.topic("payments")
+	for p, replicas := range topic.replicas {
+		if len(replicas) != 3 {
+			t.Errorf("partition %d has the replicas %v, expected 3 replicas", p, replicas)
+		}
+	}
+	// the reassignment was in progress once, then complete
+	if k.listings < 2 {
+		t.Errorf("the reassignments were listed %d times, expected the waiter to wait", k.listings)
+	}
+}
+
+func TestTopicsUpdate_decreasePartitions(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+
+	_, diags := applyTopic(t, k.client(t, ""), testTopicAttributes(3, 2, ""), testTopicAttributes(2, 2, ""))
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "cannot remove partitions") {
+		t.Fatalf("expected an error decreasing the partitions, got %v", diags)
+	}
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("partitions")) {
+		t.Errorf("the error is about %v, expected partitions", diags[0].AttributePath)
+	}
+	if n := k.requests(isCreatePartitions); n != 0 {
+		t.Errorf("%d CreatePartitions sent to the controller, expected none", n)
+	}
+}
+
+func TestTopicsRead_removed(t *testing.T) {
+	for _, api := range []string{"rest", "admin"} {
+		k := newFakeKafka(t, newFakeConfluent(t))
+		c := k.client(t, api)
+
+		d := schema.TestResourceDataRaw(t, topics().Schema, testTopicAttributes(3, 2, ""))
+		d.SetId(buildId(fakeClusterId, "payments"))
+		if diags := topicsRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("%s: %v", api, diags)
+		}
+		if d.Id() != "" {
+			t.Errorf("%s: the ID is %s, expected it to be cleared", api, d.Id())
+		}
+	}
+}
+
+func TestTopicsUpdate_adminAPI(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, map[string]string{"retention.ms": "86400000"})
+
+	_, diags := applyTopic(t, k.client(t, "admin"), testTopicAttributes(3, 2, "86400000"), testTopicAttributes(4, 2, "172800000"))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	topic, _ := k.topic("payments")
+	if len(topic.replicas) != 4 {
+		t.Errorf("topic has %d partitions, expected 4", len(topic.replicas))
+	}
+	if v := topic.configs["retention.ms"]; v != "172800000" {
+		t.Errorf("retention.ms is %s, expected 172800000", v)
+	}
+	if n := k.requests(func(r interface{}) bool { _, ok := r.(*sarama.IncrementalAlterConfigsRequest); return ok }); n != 1 {
+		t.Errorf("%d IncrementalAlterConfigs sent to the controller, expected 1", n)
+	}
+}
+
+func TestTopicsUpdate_removedConfig(t *testing.T) {
+	for _, api := range []string{"", "admin"} {
+		rest := newFakeConfluent(t)
+		k := newFakeKafka(t, rest)
+		k.createTopic("payments", 3, 2, map[string]string{"retention.ms": "86400000", "cleanup.policy": "compact"})
+
+		state := testTopicAttributes(3, 2, "86400000")
+		state["config"].(map[string]interface{})["cleanup.policy"] = "compact"
+		config := testTopicAttributes(3, 2, "")
+		config["config"] = map[string]interface{}{"cleanup.policy": "compact"}
+		_, diags := applyTopic(t, k.client(t, api), state, config)
+		if diags.HasError() {
+			t.Fatalf("%s: unexpected error: %v", api, diags)
+		}
+		var configs map[string]string
+		if api == "admin" {
+			topic, _ := k.topic("payments")
+			configs = topic.configs
+		} else {
+			topic, _ := rest.topic("payments")
+			configs = topic.configs
+		}
+		if v, ok := configs["retention.ms"]; ok {
+			t.Errorf("%s: retention.ms is still %s, expected it to be reset", api, v)
+		}
+	}
+}
+
+func TestTopicsUpdate_restAPIReplicationFactor(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+	c := k.client(t, "rest")
+
+	// the plan fails
+	d := schema.TestResourceDataRaw(t, topics().Schema, testTopicAttributes(3, 2, ""))
+	d.SetId(buildId(fakeClusterId, "payments"))
+	_, err := topics().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(testTopicAttributes(3, 3, "")), c)
+	if err == nil || !strings.Contains(err.Error(), `topic_api = "rest"`) {
+		t.Fatalf("expected the plan to fail changing the replication factor over REST, got %v", err)
+	}
+
+	// a placement constraint sets the replicas instead
+	placement := testTopicAttributes(3, 3, "")
+	placement["config"] = map[string]interface{}{"confluent.placement.constraints": "{}"}
+	if _, err := topics().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(placement), c); err != nil {
+		t.Errorf("expected the placement constraints to be accepted, got %v", err)
+	}
+
+	// the backend still refuses it
+	err = c.topics.UpdateReplicationsFactor(confluent.Topic{ClusterID: fakeClusterId, Name: "payments", ReplicationFactor: 3})
+	if err == nil || !strings.Contains(err.Error(), `topic_api = "rest"`) {
+		t.Errorf("expected an error changing the replication factor over REST, got %v", err)
+	}
+}
+
+func TestWaitForRFUpdate_timeout(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+	k.reassignPolls = 1000
+	c := k.client(t, "")
+
+	if err := c.topics.UpdateReplicationsFactor(confluent.Topic{ClusterID: fakeClusterId, Name: "payments", ReplicationFactor: 3}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := waitForRFUpdate(ctx, c.topics, "payments")
+	if err == nil || !strings.Contains(err.Error(), "replication_factor to update") {
+		t.Fatalf("expected the waiter to time out, got %v", err)
+	}
+}
+
+func TestWaitForTopicDelete(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+
+	for _, api := range []string{"", "admin"} {
+		c := k.client(t, api)
+		k.createTopic("payments", 3, 2, nil)
+		if err := c.topics.DeleteTopic(fakeClusterId, "payments"); err != nil {
+			t.Fatal(err)
+		}
+		if err := waitForTopicDelete(context.Background(), c.topics, "payments", fakeClusterId); err != nil {
+			t.Errorf("topic_api %q: %s", api, err)
+		}
+	}
+}
+
+func isCreatePartitions(r interface{}) bool {
+	_, ok := r.(*sarama.CreatePartitionsRequest)
+	return ok
+}
+
+func testTopicAttributes(partitions, replicationFactor int, retention string) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"cluster_id":         fakeClusterId,
+		"name":               "payments",
+		"partitions":         partitions,
+		"replication_factor": replicationFactor,
+	}
+	if retention != "" {
+		attributes["config"] = map[string]interface{}{"retention.ms": retention}
+	}
+	return attributes
+}
@@ -0,0 +1,199 @@
+package cplatform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const rolesPath = "/security/1.0/roles"
+
+// roleScope is where a role of MDS can be bound
+type roleScope struct {
+	// Resource is true for the roles bound to resources, false for the roles bound to a whole cluster
+	Resource bool
+	// ClusterTypes are the cluster_type the role can be bound at
+	ClusterTypes []string
+	// ResourceTypes are the types of the resources a resource role can be bound to
+	ResourceTypes []string
+}
+
+var (
+	// clusterResourceTypes are the types of the resources of each cluster_type
+	clusterResourceTypes = map[string][]string{
+		"Kafka":          {"Topic", "Group", "Cluster", "TransactionalId"},
+		"SchemaRegistry": {"Subject"},
+		"Connect":        {"Connector"},
+		"KSQL":           {"KsqlCluster"},
+	}
+
+	// defaultRoleMatrix are the predefined roles of Confluent Platform
+	defaultRoleMatrix = map[string]roleScope{
+		"SystemAdmin":     {ClusterTypes: validCluster},
+		"ClusterAdmin":    {ClusterTypes: validCluster},
+		"UserAdmin":       {ClusterTypes: validCluster},
+		"SecurityAdmin":   {ClusterTypes: []string{"Kafka", "SchemaRegistry", "Connect"}},
+		"AuditAdmin":      {ClusterTypes: []string{"Kafka"}},
+		"Operator":        {ClusterTypes: validCluster},
+		"ResourceOwner":   {Resource: true, ClusterTypes: validCluster, ResourceTypes: []string{"Topic", "Group", "Cluster", "TransactionalId", "Subject", "Connector", "KsqlCluster"}},
+		"DeveloperRead":   {Resource: true, ClusterTypes: validCluster, ResourceTypes: []string{"Topic", "Group", "Subject", "Connector", "KsqlCluster"}},
+		"DeveloperWrite":  {Resource: true, ClusterTypes: validCluster, ResourceTypes: []string{"Topic", "Cluster", "TransactionalId", "Subject", "Connector", "KsqlCluster"}},
+		"DeveloperManage": {Resource: true, ClusterTypes: validCluster, ResourceTypes: []string{"Topic", "Group", "Cluster", "TransactionalId", "Subject", "Connector", "KsqlCluster"}},
+	}
+)
+
+// roleMatrix returns the roles refreshed from MDS, or the predefined roles
+func (c *Client) roleMatrix() map[string]roleScope {
+	if c.roles != nil {
+		return c.roles
+	}
+	return defaultRoleMatrix
+}
+
+// mdsRole is a role returned by MDS, the newer versions return several policies instead of accessPolicy
+type mdsRole struct {
+	Name         string            `json:"name"`
+	AccessPolicy *mdsAccessPolicy  `json:"accessPolicy"`
+	Policies     []mdsAccessPolicy `json:"policies"`
+}
+
+type mdsAccessPolicy struct {
+	ScopeType         string `json:"scopeType"`
+	BindWithResource  bool   `json:"bindWithResource"`
+	AllowedOperations []struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"allowedOperations"`
+}
+
+// listRoles returns the roles of MDS
+func listRoles(c restAPI) ([]mdsRole, error) {
+	r, err := c.DoRequest("GET", rolesPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var roles []mdsRole
+	if err := json.Unmarshal(r, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// roleMatrixOf builds the role matrix from the roles of MDS.
+// MDS tells if a role is bound to resources and the types of these resources, the clusters of the predefined
+// roles are kept, the other roles can be bound at the clusters of their resource types, or at any cluster.
+func roleMatrixOf(roles []mdsRole) map[string]roleScope {
+	matrix := make(map[string]roleScope, len(roles))
+	for _, r := range roles {
+		policies := r.Policies
+		if r.AccessPolicy != nil {
+			policies = append(policies, *r.AccessPolicy)
+		}
+
+		var s roleScope
+		resourceTypes := make(map[string]struct{})
+		for _, p := range policies {
+			if p.BindWithResource || strings.EqualFold(p.ScopeType, "resource") {
+				s.Resource = true
+			}
+			for _, o := range p.AllowedOperations {
+				resourceTypes[o.ResourceType] = struct{}{}
+			}
+		}
+
+		if s.Resource {
+			for t := range resourceTypes {
+				s.ResourceTypes = append(s.ResourceTypes, t)
+			}
+			sort.Strings(s.ResourceTypes)
+		}
+		if d, ok := defaultRoleMatrix[r.Name]; ok {
+			s.ClusterTypes = d.ClusterTypes
+		} else {
+			s.ClusterTypes = clusterTypesOf(s.ResourceTypes)
+		}
+		matrix[r.Name] = s
+	}
+	return matrix
+}
+
+// clusterTypesOf returns the cluster_type of the resource types, or every cluster_type without resource types
+func clusterTypesOf(resourceTypes []string) []string {
+	var types []string
+	for _, t := range validCluster {
+		for _, rt := range clusterResourceTypes[t] {
+			if contains(resourceTypes, rt) {
+				types = append(types, t)
+				break
+			}
+		}
+	}
+	if len(types) == 0 {
+		return validCluster
+	}
+	return types
+}
+
+// validateRoleScope checks that the role can be bound at the cluster_type, to a resource of resourceType
+// when it is not empty, or to the whole cluster
+func validateRoleScope(matrix map[string]roleScope, role, clusterType, resourceType string) error {
+	s, ok := matrix[role]
+	if !ok {
+		return fmt.Errorf("unknown role %s, expected one of %s", role, strings.Join(roleNames(matrix), ", "))
+	}
+
+	switch {
+	case resourceType == "" && s.Resource:
+		return fmt.Errorf("%s is a role of the resources, it cannot be bound to a whole cluster: use kafka_topic_rbac, schema_registry_rbac or connectors_rbac", role)
+	case resourceType != "" && !s.Resource:
+		return fmt.Errorf("%s is a role of the clusters, it cannot be bound to a resource: use cluster_role_binding", role)
+	}
+	if !contains(s.ClusterTypes, clusterType) {
+		return fmt.Errorf("%s cannot be bound at a %s cluster, only at %s", role, clusterType, strings.Join(s.ClusterTypes, ", "))
+	}
+	if resourceType != "" {
+		if !contains(clusterResourceTypes[clusterType], resourceType) {
+			return fmt.Errorf("a %s cluster has no resource of type %s, expected %s", clusterType, resourceType, strings.Join(clusterResourceTypes[clusterType], ", "))
+		}
+		if !contains(s.ResourceTypes, resourceType) {
+			return fmt.Errorf("%s cannot be bound to a resource of type %s, only to %s", role, resourceType, strings.Join(s.ResourceTypes, ", "))
+		}
+	}
+	return nil
+}
+
+func roleNames(matrix map[string]roleScope) []string {
+	names := make([]string, 0, len(matrix))
+	for n := range matrix {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// roleScopeCustomizeDiff fails the plan of a role binding MDS would reject. scope returns its cluster_type and the
+// type of its resource, "" for a binding of the whole cluster, known is false while they are not known.
+// Without refresh_roles a role which is not predefined may be a custom role of MDS, its scope is not checked.
+func roleScopeCustomizeDiff(scope func(d *schema.ResourceDiff) (clusterType, resourceType string, known bool)) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		clusterType, resourceType, known := scope(d)
+		if !known || !d.NewValueKnown("role") {
+			return nil
+		}
+
+		role := d.Get("role").(string)
+		c, ok := meta.(*Client)
+		if !ok || c.roles == nil {
+			if _, predefined := defaultRoleMatrix[role]; !predefined {
+				tflog.SubsystemWarn(ctx, logMDS, "The role is not predefined, its scope is checked by MDS: set refresh_roles to check the custom roles at plan", map[string]interface{}{"role": role})
+				return nil
+			}
+			return validateRoleScope(defaultRoleMatrix, role, clusterType, resourceType)
+		}
+		return validateRoleScope(c.roles, role, clusterType, resourceType)
+	}
+}
@@ -0,0 +1,127 @@
+package cplatform
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateRoleScope(t *testing.T) {
+	cases := []struct {
+		role, clusterType, resourceType string
+		err                             string
+	}{
+		{role: "SystemAdmin", clusterType: "KSQL"},
+		{role: "DeveloperRead", clusterType: "Kafka", resourceType: "Topic"},
+		{role: "ResourceOwner", clusterType: "SchemaRegistry", resourceType: "Subject"},
+		{role: "DeveloperRead", clusterType: "Kafka", err: "cannot be bound to a whole cluster"},
+		{role: "SystemAdmin", clusterType: "Kafka", resourceType: "Topic", err: "cannot be bound to a resource"},
+		{role: "AuditAdmin", clusterType: "KSQL", err: "cannot be bound at a KSQL cluster"},
+		{role: "SecurityAdmin", clusterType: "KSQL", err: "cannot be bound at a KSQL cluster"},
+		{role: "DeveloperWrite", clusterType: "Kafka", resourceType: "Group", err: "only to"},
+		{role: "DeveloperRead", clusterType: "Kafka", resourceType: "Subject", err: "has no resource of type Subject"},
+		{role: "Operation", clusterType: "Kafka", err: "unknown role"},
+	}
+	for _, c := range cases {
+		err := validateRoleScope(defaultRoleMatrix, c.role, c.clusterType, c.resourceType)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%s at %s %s: %v", c.role, c.clusterType, c.resourceType, err)
+		case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("%s at %s %s: %v, expected %q", c.role, c.clusterType, c.resourceType, err, c.err)
+		}
+	}
+}
+
+func TestRoleMatrix_refreshedFromMDS(t *testing.T) {
+	f := newFakeConfluent(t)
+	c, err := f.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles, err := listRoles(c.rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := roleMatrixOf(roles)
+
+	want := map[string]roleScope{
+		"SystemAdmin":   {ClusterTypes: validCluster},
+		"DeveloperRead": {Resource: true, ClusterTypes: validCluster, ResourceTypes: []string{"Group", "Topic"}},
+		"SubjectReader": {Resource: true, ClusterTypes: []string{"SchemaRegistry"}, ResourceTypes: []string{"Subject"}},
+	}
+	if !reflect.DeepEqual(matrix, want) {
+		t.Errorf("roleMatrixOf() = %+v, want %+v", matrix, want)
+	}
+	if err := validateRoleScope(matrix, "DeveloperManage", "Kafka", "Topic"); err == nil {
+		t.Error("expected a role unknown to MDS to be rejected")
+	}
+}
+
+func TestClusterRoleBindings_customizeDiff(t *testing.T) {
+	r := clusterRoleBindings()
+	config := map[string]interface{}{
+		"principal":    "User:alice",
+		"role":         "DeveloperRead",
+		"cluster_type": "Kafka",
+		"cluster_id":   fakeClusterId,
+	}
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), &Client{})
+	if err == nil || !strings.Contains(err.Error(), "cannot be bound to a whole cluster") {
+		t.Errorf("expected DeveloperRead without resource to be rejected, got %v", err)
+	}
+
+	config["role"] = "UserAdmin"
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), &Client{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSchemaRegistryRBAC_customRole(t *testing.T) {
+	f := newFakeConfluent(t)
+	c, err := f.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles, err := listRoles(c.rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.roles = roleMatrixOf(roles)
+
+	r := schemaRegistryRBAC()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"principal":                  "User:alice",
+		"role":                       "SubjectReader",
+		"name":                       "payments-",
+		"pattern_type":               "PREFIXED",
+		"cluster_id":                 fakeClusterId,
+		"schema_registry_cluster_id": "schema-registry",
+	})
+	if diags := r.Validate(config); diags.HasError() {
+		t.Fatalf("the custom role is rejected by the schema: %v", diags)
+	}
+	if _, err := r.Diff(context.Background(), nil, config, c); err != nil {
+		t.Errorf("the custom role is rejected with the roles of MDS: %v", err)
+	}
+	// without refresh_roles the custom role is left to MDS
+	if _, err := r.Diff(context.Background(), nil, config, &Client{}); err != nil {
+		t.Errorf("expected the custom role to be planned without refresh_roles, got %v", err)
+	}
+
+	// a role unknown to the refreshed roles is rejected
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"principal":                  "User:alice",
+		"role":                       "SubjectWriter",
+		"name":                       "payments-",
+		"pattern_type":               "PREFIXED",
+		"cluster_id":                 fakeClusterId,
+		"schema_registry_cluster_id": "schema-registry",
+	})
+	if _, err := r.Diff(context.Background(), nil, config, c); err == nil || !strings.Contains(err.Error(), "unknown role") {
+		t.Errorf("expected the role to be unknown with the roles of MDS, got %v", err)
+	}
+}
@@ -0,0 +1,343 @@
+package cplatform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	confluent "github.com/OneMount/gonfluent"
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	// errTopicNotFound is returned by the admin backend, the REST backend returns a 404
+	errTopicNotFound = errors.New("topic not found")
+
+	// configSources maps the sources of the Kafka protocol to the sources of the REST API
+	configSources = map[sarama.ConfigSource]string{
+		sarama.SourceTopic:                "DYNAMIC_TOPIC_CONFIG",
+		sarama.SourceDynamicBroker:        "DYNAMIC_BROKER_CONFIG",
+		sarama.SourceDynamicDefaultBroker: "DYNAMIC_DEFAULT_BROKER_CONFIG",
+		sarama.SourceStaticBroker:         "STATIC_BROKER_CONFIG",
+		sarama.SourceDefault:              "DEFAULT_CONFIG",
+	}
+)
+
+// topicAPI is the backend of kafka_topic, chosen by topic_api of the provider.
+// *confluent.Client is the default: the topics and their configs over REST, the partitions and the replicas
+// with the Kafka admin client.
+type topicAPI interface {
+	GetTopic(clusterId, topicName string) (*confluent.Topic, error)
+	CreateTopic(clusterId, topicName string, partitionsCount, replicationFactor int, configs []confluent.TopicConfig, replicasAssignments []confluent.ReplicasAssignment) error
+	DeleteTopic(clusterId, topicName string) error
+	UpdatePartitions(t confluent.Topic) error
+	UpdateReplicationsFactor(t confluent.Topic) error
+	IsReplicationFactorUpdating(topic string) (bool, error)
+	UpdateTopicConfigs(clusterId, topicName string, configs []confluent.TopicConfig) error
+}
+
+func newTopicAPI(c *Client, client *confluent.Client, api string) topicAPI {
+	switch api {
+	case "rest":
+		return restTopicAPI{client}
+	case "admin":
+		return adminTopicAPI{Client: client, c: c}
+	default:
+		return client
+	}
+}
+
+// topicsBackendCustomizeDiff fails the plan of what the backends cannot do: a new replication factor over the REST
+// API, and the features using the Kafka protocol when only the REST API is used
+func topicsBackendCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	c, ok := meta.(*Client)
+	if !ok {
+		return nil
+	}
+	name := d.Get("name").(string)
+	_, placement := d.Get("config").(map[string]interface{})["confluent.placement.constraints"]
+	if _, rest := c.topics.(restTopicAPI); rest && d.Id() != "" && d.HasChange("replication_factor") && !placement {
+		return fmt.Errorf("the replication factor of topic %s cannot be changed with topic_api = \"rest\", the REST API cannot reassign the partitions", name)
+	}
+	if c.kafka != nil {
+		return nil
+	}
+	if d.Get("deletion_check_consumers").(bool) {
+		return fmt.Errorf("deletion_check_consumers of topic %s lists the consumer groups: %w", name, errNoKafka)
+	}
+	if d.Id() != "" && d.HasChange("name") && d.Get("copy_on_rename").(bool) {
+		return fmt.Errorf("copy_on_rename of topic %s copies the messages: %w", name, errNoKafka)
+	}
+	return nil
+}
+
+// topicSummaries lists the topics of the cluster, with the admin client when topic_api is admin and the REST API otherwise
+func (c *Client) topicSummaries(clusterId string) ([]topicSummary, error) {
+	if a, ok := c.topics.(adminTopicAPI); ok {
+		return a.listTopics()
+	}
+	return listTopics(c.rest, clusterId)
+}
+
+// topicReplicas returns the replicas of every partition of the topic by partition ID, with the admin client when
+// topic_api is admin and the REST API otherwise
+func (c *Client) topicReplicas(clusterId, topicName string) (map[int][]topicReplica, error) {
+	if a, ok := c.topics.(adminTopicAPI); ok {
+		return a.replicas(topicName)
+	}
+	return getTopicReplicas(c.rest, clusterId, topicName)
+}
+
+// deleteTopicConfigs resets the configs of the topic to their defaults, UpdateTopicConfigs of gonfluent only sets configs
+func (c *Client) deleteTopicConfigs(clusterId, topicName string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if _, ok := c.topics.(adminTopicAPI); ok {
+		entries := make(map[string]sarama.IncrementalAlterConfigsEntry)
+		for _, name := range names {
+			entries[name] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+		}
+		return c.incrementalAlterConfigs(sarama.ConfigResource{Type: sarama.TopicResource, Name: topicName}, entries)
+	}
+
+	type operation struct {
+		Name      string `json:"name"`
+		Operation string `json:"operation"`
+	}
+	body := struct {
+		Data []operation `json:"data"`
+	}{}
+	for _, name := range names {
+		body.Data = append(body.Data, operation{Name: name, Operation: "DELETE"})
+	}
+	u := "/kafka/v3/clusters/" + url.PathEscape(clusterId) + "/topics/" + url.PathEscape(topicName) + "/configs:alter"
+	return doRest(c.rest, "POST", u, body, nil)
+}
+
+func isTopicNotFound(err error) bool {
+	return isNotFound(err) || strings.Contains(err.Error(), "404")
+}
+
+// restTopicAPI only uses the REST API of Confluent Server, for when only its HTTPS port is reachable
+type restTopicAPI struct {
+	*confluent.Client
+}
+
+func (r restTopicAPI) UpdatePartitions(t confluent.Topic) error {
+	body := struct {
+		PartitionsCount int32 `json:"partitions_count"`
+	}{
+		PartitionsCount: t.Partitions,
+	}
+	u := "/kafka/v3/clusters/" + url.PathEscape(t.ClusterID) + "/topics/" + url.PathEscape(t.Name)
+	return doRest(r.Client, "PATCH", u, body, nil)
+}
+
+func (r restTopicAPI) UpdateReplicationsFactor(t confluent.Topic) error {
+	return fmt.Errorf("the replication factor of topic %s cannot be changed with topic_api = \"rest\", the REST API cannot reassign the partitions", t.Name)
+}
+
+func (r restTopicAPI) IsReplicationFactorUpdating(string) (bool, error) {
+	return false, nil
+}
+
+// adminTopicAPI only uses the Kafka protocol, the partitions and the replicas are already changed with
+// the Kafka admin client by gonfluent
+type adminTopicAPI struct {
+	*confluent.Client
+	c *Client
+}
+
+func (a adminTopicAPI) GetTopic(clusterId, topicName string) (*confluent.Topic, error) {
+	admin, err := a.c.kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := admin.DescribeTopics([]string{topicName})
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata) == 0 || metadata[0].Err == sarama.ErrUnknownTopicOrPartition {
+		return nil, fmt.Errorf("%s: %w", topicName, errTopicNotFound)
+	}
+	if err := kafkaError(metadata[0].Err, nil); err != nil {
+		return nil, err
+	}
+
+	topic := &confluent.Topic{
+		ClusterID:  clusterId,
+		Name:       metadata[0].Name,
+		IsInternal: metadata[0].IsInternal,
+		Partitions: int32(len(metadata[0].Partitions)),
+	}
+	for _, p := range metadata[0].Partitions {
+		if p.ID == 0 {
+			topic.ReplicationFactor = int16(len(p.Replicas))
+		}
+		topic.PartitionsDetails = append(topic.PartitionsDetails, confluent.Partition{
+			ClusterID:   clusterId,
+			TopicName:   topicName,
+			PartitionId: int(p.ID),
+		})
+	}
+	sort.Slice(topic.PartitionsDetails, func(i, j int) bool {
+		return topic.PartitionsDetails[i].PartitionId < topic.PartitionsDetails[j].PartitionId
+	})
+
+	entries, err := admin.DescribeConfig(sarama.ConfigResource{
+		Type: sarama.TopicResource,
+		Name: topicName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		source, ok := configSources[e.Source]
+		if !ok {
+			source = "UNKNOWN"
+		}
+		topic.Config = append(topic.Config, confluent.TopicConfig{
+			ClusterId:   clusterId,
+			TopicName:   topicName,
+			Name:        e.Name,
+			Value:       e.Value,
+			IsDefault:   e.Default,
+			IsReadOnly:  e.ReadOnly,
+			IsSensitive: e.Sensitive,
+			Source:      source,
+		})
+	}
+
+	return topic, nil
+}
+
+func (a adminTopicAPI) CreateTopic(_, topicName string, partitionsCount, replicationFactor int, configs []confluent.TopicConfig, _ []confluent.ReplicasAssignment) error {
+	admin, err := a.c.kafkaAdmin()
+	if err != nil {
+		return err
+	}
+
+	detail := &sarama.TopicDetail{
+		NumPartitions:     int32(partitionsCount),
+		ReplicationFactor: int16(replicationFactor),
+		ConfigEntries:     make(map[string]*string),
+	}
+	// The default of the brokers, or the placement constraints
+	if replicationFactor == 0 {
+		detail.ReplicationFactor = -1
+	}
+	for _, v := range configs {
+		v := v
+		detail.ConfigEntries[v.Name] = &v.Value
+	}
+
+	return admin.CreateTopic(topicName, detail, false)
+}
+
+func (a adminTopicAPI) DeleteTopic(_, topicName string) error {
+	admin, err := a.c.kafkaAdmin()
+	if err != nil {
+		return err
+	}
+	if err := admin.DeleteTopic(topicName); err != nil {
+		if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
+			return fmt.Errorf("%s: %w", topicName, errTopicNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// UpdateTopicConfigs only sets the given configs, like the REST API, the removed configs are reset by deleteTopicConfigs
+func (a adminTopicAPI) UpdateTopicConfigs(_, topicName string, configs []confluent.TopicConfig) error {
+	entries := make(map[string]sarama.IncrementalAlterConfigsEntry)
+	for _, v := range configs {
+		v := v
+		entries[v.Name] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &v.Value}
+	}
+	return a.c.incrementalAlterConfigs(sarama.ConfigResource{Type: sarama.TopicResource, Name: topicName}, entries)
+}
+
+// listTopics lists the topics of the cluster with their partitions
+func (a adminTopicAPI) listTopics() ([]topicSummary, error) {
+	admin, err := a.c.kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := admin.ListTopics()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(details))
+	for name := range details {
+		names = append(names, name)
+	}
+	// ListTopics does not tell the internal topics
+	metadata, err := admin.DescribeTopics(names)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make([]topicSummary, 0, len(metadata))
+	for _, m := range metadata {
+		if err := kafkaError(m.Err, nil); err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Name, err)
+		}
+		t := topicSummary{Name: m.Name, IsInternal: m.IsInternal, PartitionsCount: len(m.Partitions)}
+		for _, p := range m.Partitions {
+			if p.ID == 0 {
+				t.ReplicationFactor = len(p.Replicas)
+			}
+		}
+		topics = append(topics, t)
+	}
+	return topics, nil
+}
+
+// replicas returns the replicas of every partition of the topic by partition ID
+func (a adminTopicAPI) replicas(topicName string) (map[int][]topicReplica, error) {
+	admin, err := a.c.kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := admin.DescribeTopics([]string{topicName})
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata) == 0 || metadata[0].Err == sarama.ErrUnknownTopicOrPartition {
+		return nil, fmt.Errorf("%s: %w", topicName, errTopicNotFound)
+	}
+	if err := kafkaError(metadata[0].Err, nil); err != nil {
+		return nil, err
+	}
+
+	replicas := make(map[int][]topicReplica)
+	for _, p := range metadata[0].Partitions {
+		for _, broker := range p.Replicas {
+			replicas[int(p.ID)] = append(replicas[int(p.ID)], topicReplica{
+				PartitionId: int(p.ID),
+				BrokerId:    int(broker),
+				IsLeader:    broker == p.Leader,
+				IsInSync:    containsBroker(p.Isr, broker),
+			})
+		}
+	}
+	return replicas, nil
+}
+
+func containsBroker(brokers []int32, broker int32) bool {
+	for _, b := range brokers {
+		if b == broker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,366 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// copyBatchSize is the number of messages produced at once by the copy
+	copyBatchSize = 500
+)
+
+// topicsRenameCustomizeDiff keeps name ForceNew unless copy_on_rename is set
+func topicsRenameCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("name") || d.Get("copy_on_rename").(bool) {
+		return nil
+	}
+	return d.ForceNew("name")
+}
+
+// topicRename creates the new topic, copies the messages and optionally the committed offsets of the consumer groups,
+// then deletes the old topic once the copy is verified. The producers and the consumers of the old topic must be stopped.
+func topicRename(ctx context.Context, c *Client, d *schema.ResourceData) error {
+	clusterId, from, err := parseTopicId(d.Id())
+	if err != nil {
+		return err
+	}
+	to := d.Get("name").(string)
+	if c.kafka == nil {
+		return errNoKafka
+	}
+
+	partitions, err := c.kafka.Partitions(from)
+	if err != nil {
+		return err
+	}
+	if d.Get("partitions").(int) < len(partitions) {
+		return fmt.Errorf("cannot decrease the number of partitions of topic %s while renaming it to %s", from, to)
+	}
+
+	// committed offsets of the consumer groups on the old topic
+	groups := make(map[string]map[int32]int64)
+	if d.Get("copy_consumer_offsets").(bool) {
+		names, err := c.topicConsumerGroups(from)
+		if err != nil {
+			return err
+		}
+		for _, group := range names {
+			g, err := c.describeConsumerGroup(group)
+			if err != nil {
+				return err
+			}
+			if len(g.Members) > 0 {
+				return fmt.Errorf("consumer group %s of topic %s is %s with %d members, stop its consumers before renaming the topic", group, from, g.State, len(g.Members))
+			}
+			offsets, err := c.committedOffsets(group, map[string][]int32{from: partitions})
+			if err != nil {
+				return err
+			}
+			groups[group] = offsets[from]
+		}
+	}
+	targets := make(map[int32][]int64)
+	for _, offsets := range groups {
+		for p, o := range offsets {
+			targets[p] = append(targets[p], o)
+		}
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, logKafka, "cluster_id", clusterId)
+	ctx = tflog.SubsystemSetField(ctx, logKafka, "topic", from)
+	ctx = tflog.SubsystemSetField(ctx, logKafka, "new_topic", to)
+	tflog.SubsystemInfo(ctx, logKafka, "Renaming the topic: creating the new topic")
+	if err := topicCreateFunc(c.topics, clusterId, d); err != nil {
+		return err
+	}
+	// The ID stays the old topic until it is deleted
+	d.SetId(buildId(clusterId, from))
+
+	tflog.SubsystemInfo(ctx, logKafka, "Renaming the topic: copying the messages")
+	translated, err := c.copyTopic(ctx, from, to, targets)
+	if err != nil {
+		// The partial copy would fail the next attempt, the old topic is kept
+		tflog.SubsystemWarn(ctx, logKafka, "Renaming the topic: deleting the partial copy", map[string]interface{}{"error": err.Error()})
+		if derr := c.topics.DeleteTopic(clusterId, to); derr != nil {
+			return fmt.Errorf("error copying topic %s to %s: %w, and %s must be deleted before retrying: %s", from, to, err, to, derr)
+		}
+		if derr := waitForTopicDelete(ctx, c.topics, to, clusterId); derr != nil {
+			return fmt.Errorf("error copying topic %s to %s: %w, and %s must be deleted before retrying: %s", from, to, err, to, derr)
+		}
+		return fmt.Errorf("error copying topic %s to %s, the partial copy %s was deleted: %w", from, to, to, err)
+	}
+
+	for group, offsets := range groups {
+		committed := make(map[int32]int64)
+		for p, o := range offsets {
+			committed[p] = translated[p][o]
+		}
+		tflog.SubsystemInfo(ctx, logKafka, "Renaming the topic: committing the offsets of the consumer group", map[string]interface{}{"group_id": group})
+		if err := c.commitOffsets(group, to, committed); err != nil {
+			return fmt.Errorf("error committing the offsets of %s on %s: %w", group, to, err)
+		}
+	}
+
+	tflog.SubsystemInfo(ctx, logKafka, "Renaming the topic: deleting the old topic")
+	if err := c.topics.DeleteTopic(clusterId, from); err != nil {
+		return err
+	}
+	if err := waitForTopicDelete(ctx, c.topics, from, clusterId); err != nil {
+		return err
+	}
+
+	d.SetId(buildId(clusterId, to))
+	return nil
+}
+
+// copyTopic copies every message of a topic into the same partition of another, empty, topic, keeping the keys,
+// the headers and the timestamps. It returns the offsets in the copy of the given offsets of the topic.
+func (c *Client) copyTopic(ctx context.Context, from, to string, targets map[int32][]int64) (map[int32]map[int64]int64, error) {
+	cfg := *c.kafka.Config()
+	cfg.Version = c.kafkaVersion
+	cfg.Consumer.Return.Errors = true
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.Return.Errors = true
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Partitioner = sarama.NewManualPartitioner
+
+	client, err := sarama.NewClient(c.bootstrapServers, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	partitions, err := client.Partitions(from)
+	if err != nil {
+		return nil, err
+	}
+	oldest := make(map[int32]int64)
+	newest := make(map[int32]int64)
+	for _, p := range partitions {
+		if oldest[p], err = client.GetOffset(from, p, sarama.OffsetOldest); err != nil {
+			return nil, err
+		}
+		if newest[p], err = client.GetOffset(from, p, sarama.OffsetNewest); err != nil {
+			return nil, err
+		}
+	}
+
+	// The new topic may not be in the metadata yet
+	err = resource.RetryContext(ctx, 2*time.Minute, func() *resource.RetryError {
+		if err := client.RefreshMetadata(to); err != nil {
+			return resource.RetryableError(err)
+		}
+		p, err := client.Partitions(to)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		if len(p) < len(partitions) {
+			return resource.RetryableError(fmt.Errorf("topic %s has %d partitions, expected %d", to, len(p), len(partitions)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+	defer producer.Close()
+
+	translated := make(map[int32]map[int64]int64)
+	for _, p := range partitions {
+		leader, err := client.Leader(from, p)
+		if err != nil {
+			return nil, err
+		}
+		copied, offsets, err := copyPartition(ctx, consumer, producer, leader, from, to, p, oldest[p], newest[p], targets[p], c.copyIdleTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("partition %d: %w", p, err)
+		}
+		translated[p] = offsets
+
+		// Verify that every message is in the copy and that nothing was produced to the topic meanwhile
+		end, err := client.GetOffset(to, p, sarama.OffsetNewest)
+		if err != nil {
+			return nil, err
+		}
+		if end != copied {
+			return nil, fmt.Errorf("partition %d: %d messages copied but %s has %d", p, copied, to, end)
+		}
+		end, err = client.GetOffset(from, p, sarama.OffsetNewest)
+		if err != nil {
+			return nil, err
+		}
+		if end != newest[p] {
+			return nil, fmt.Errorf("partition %d: messages were produced to %s during the copy, stop its producers", p, from)
+		}
+	}
+
+	return translated, nil
+}
+
+// copyPartition copies the messages from oldest to newest and returns the number of messages copied, and the offsets
+// in the copy of the targets: the offset of the first message copied at or after each of them.
+// It fails unless every offset up to newest is copied or is a control record, checked on the leader of the partition
+// once no message was consumed for idleTimeout.
+func copyPartition(ctx context.Context, consumer sarama.Consumer, producer sarama.SyncProducer, leader *sarama.Broker, from, to string, p int32, oldest, newest int64, targets []int64, idleTimeout time.Duration) (int64, map[int64]int64, error) {
+	translated := make(map[int64]int64)
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	var copied int64
+	if oldest >= newest {
+		for _, t := range targets {
+			translated[t] = 0
+		}
+		return copied, translated, nil
+	}
+
+	pc, err := consumer.ConsumePartition(from, p, oldest)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer pc.Close()
+
+	var batch []*sarama.ProducerMessage
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := producer.SendMessages(batch); err != nil {
+			return err
+		}
+		for _, m := range batch {
+			for len(targets) > 0 && targets[0] <= m.Metadata.(int64) {
+				translated[targets[0]] = m.Offset
+				targets = targets[1:]
+			}
+		}
+		copied += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+consume:
+	for next := oldest; next < newest; {
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case err := <-pc.Errors():
+			return 0, nil, err
+		case m := <-pc.Messages():
+			batch = append(batch, copyMessage(m, to))
+			next = m.Offset + 1
+			if len(batch) >= copyBatchSize {
+				if err := flush(); err != nil {
+					return 0, nil, err
+				}
+			}
+		case <-time.After(idleTimeout):
+			// The consumer does not return the control records
+			control, err := controlRecordsOnly(leader, from, p, next, newest)
+			if err != nil {
+				return 0, nil, err
+			}
+			if !control {
+				return 0, nil, fmt.Errorf("no message after offset %d for %s, the copy stopped before the end of the partition at offset %d", next, idleTimeout, newest)
+			}
+			tflog.SubsystemDebug(ctx, logKafka, "The last offsets of the partition are control records", map[string]interface{}{
+				"topic":     from,
+				"partition": p,
+				"offset":    next,
+			})
+			break consume
+		}
+	}
+	if err := flush(); err != nil {
+		return 0, nil, err
+	}
+
+	// The consumers were at the end of the partition
+	for _, t := range targets {
+		translated[t] = copied
+	}
+	return copied, translated, nil
+}
+
+// controlRecordsOnly tells if every record of the partition from next to newest is a control record,
+// a transaction marker for example
+func controlRecordsOnly(leader *sarama.Broker, topic string, p int32, next, newest int64) (bool, error) {
+	for next < newest {
+		req := &sarama.FetchRequest{Version: 4, MaxWaitTime: 500, MinBytes: 1, MaxBytes: 1 << 20, Isolation: sarama.ReadUncommitted}
+		req.AddBlock(topic, p, next, 1<<20)
+		rsp, err := leader.Fetch(req)
+		if err != nil {
+			return false, err
+		}
+		block := rsp.GetBlock(topic, p)
+		if block == nil {
+			return false, fmt.Errorf("no records of partition %d in the fetch response", p)
+		}
+		if err := kafkaError(block.Err, nil); err != nil {
+			return false, err
+		}
+
+		fetched := false
+		for _, records := range block.RecordsSet {
+			batch := records.RecordBatch
+			// The messages of the legacy format are never control records
+			if batch == nil {
+				return false, nil
+			}
+			if batch.FirstOffset >= newest {
+				return true, nil
+			}
+			last := batch.FirstOffset + int64(batch.LastOffsetDelta)
+			if last < next {
+				continue
+			}
+			if !batch.Control {
+				for _, r := range batch.Records {
+					if o := batch.FirstOffset + r.OffsetDelta; o >= next && o < newest {
+						return false, nil
+					}
+				}
+			}
+			next, fetched = last+1, true
+		}
+		if !fetched {
+			return false, fmt.Errorf("cannot fetch the offset %d of partition %d", next, p)
+		}
+	}
+	return true, nil
+}
+
+func copyMessage(m *sarama.ConsumerMessage, to string) *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic:     to,
+		Partition: m.Partition,
+		Timestamp: m.Timestamp,
+		Metadata:  m.Offset,
+	}
+	if m.Key != nil {
+		msg.Key = sarama.ByteEncoder(m.Key)
+	}
+	if m.Value != nil {
+		msg.Value = sarama.ByteEncoder(m.Value)
+	}
+	for _, h := range m.Headers {
+		msg.Headers = append(msg.Headers, *h)
+	}
+	return msg
+}
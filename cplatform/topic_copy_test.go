@@ -0,0 +1,72 @@
+package cplatform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+)
+
+func TestControlRecordsOnly(t *testing.T) {
+	// offsets 0 and 1 are messages of a transaction, 2 is its commit marker
+	fetch := &sarama.FetchResponse{Version: 4}
+	fetch.AddRecordBatch("payments", 0, nil, sarama.StringEncoder("a"), 0, 1, true)
+	fetch.AddRecordBatch("payments", 0, nil, sarama.StringEncoder("b"), 1, 1, true)
+	fetch.AddControlRecord("payments", 0, 2, 1, sarama.ControlRecordCommit)
+
+	mock := sarama.NewMockBroker(t, 1)
+	mock.SetHandlerByMap(map[string]sarama.MockResponse{
+		"FetchRequest": sarama.NewMockWrapper(fetch),
+	})
+	defer mock.Close()
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V2_7_0_0
+	leader := sarama.NewBroker(mock.Addr())
+	if err := leader.Open(config); err != nil {
+		t.Fatal(err)
+	}
+	defer leader.Close()
+
+	for _, tc := range []struct {
+		next    int64
+		control bool
+	}{
+		{next: 2, control: true},
+		// the copy stopped before the last message
+		{next: 1, control: false},
+	} {
+		control, err := controlRecordsOnly(leader, "payments", 0, tc.next, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if control != tc.control {
+			t.Errorf("from offset %d: control records only is %t, expected %t", tc.next, control, tc.control)
+		}
+	}
+}
+
+func TestTopicRename_copyFailure(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+	c := k.client(t, "")
+	// the client has no bootstrap_servers, the copy cannot connect to any broker
+	c.kafkaVersion = sarama.V2_7_0_0
+
+	state := testTopicAttributes(3, 2, "")
+	state["copy_on_rename"] = true
+	config := testTopicAttributes(3, 2, "")
+	config["name"] = "payments-v2"
+	config["copy_on_rename"] = true
+
+	_, diags := applyTopic(t, c, state, config)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "the partial copy payments-v2 was deleted") {
+		t.Fatalf("expected the copy to fail, got %v", diags)
+	}
+	if _, ok := k.topic("payments-v2"); ok {
+		t.Errorf("the partial copy was not deleted")
+	}
+	if _, ok := k.topic("payments"); !ok {
+		t.Errorf("the old topic was deleted")
+	}
+}
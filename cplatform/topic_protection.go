@@ -0,0 +1,123 @@
+package cplatform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// isTopicProtected tells if the topic has deletion_protection or matches protect_topics_matching of the provider
+func (c *Client) isTopicProtected(topic string, deletionProtection bool) (bool, string) {
+	if deletionProtection {
+		return true, "deletion_protection is true"
+	}
+	for _, re := range c.protectTopics {
+		if re.MatchString(topic) {
+			return true, fmt.Sprintf("it matches %q of the provider protect_topics_matching", re.String())
+		}
+	}
+	return false, ""
+}
+
+// topicConsumerGroups returns the groups having committed offsets on the topic
+func (c *Client) topicConsumerGroups(topic string) ([]string, error) {
+	admin, err := c.kafkaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := admin.ListConsumerGroups()
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := c.kafka.Partitions(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumers []string
+	for group := range groups {
+		offsets, err := c.committedOffsets(group, map[string][]int32{topic: partitions})
+		if err != nil {
+			return nil, err
+		}
+		if len(offsets[topic]) > 0 {
+			consumers = append(consumers, group)
+		}
+	}
+	sort.Strings(consumers)
+	return consumers, nil
+}
+
+// topicDeletionDiags returns an error when the topic must not be deleted. Every path deleting a topic calls it:
+// the deletion or the replacement of kafka_topic, its rename by copy and the deletion of kafka_mirror_topic.
+func topicDeletionDiags(c *Client, topicName string, deletionProtection, checkConsumers bool) diag.Diagnostics {
+	if protected, reason := c.isTopicProtected(topicName, deletionProtection); protected {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Topic %s is protected against deletion", topicName),
+				Detail: fmt.Sprintf("The topic cannot be deleted or replaced because %s. "+
+					"Set deletion_protection = false and remove the topic from protect_topics_matching, then apply again to delete it.", reason),
+				AttributePath: cty.GetAttrPath("deletion_protection"),
+			},
+		}
+	}
+
+	if checkConsumers {
+		consumers, err := c.topicConsumerGroups(topicName)
+		if err != nil {
+			return errorDiags("Cannot list the consumer groups of topic "+topicName, err, "deletion_check_consumers")
+		}
+		if len(consumers) > 0 {
+			return diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Topic %s still has consumers", topicName),
+					Detail: fmt.Sprintf("The consumer groups %s have committed offsets on the topic. "+
+						"Delete their offsets or set deletion_check_consumers = false to delete it.", strings.Join(consumers, ", ")),
+					AttributePath: cty.GetAttrPath("deletion_check_consumers"),
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+// topicsCustomizeDiff fails the plan when a protected topic would be replaced, or renamed with copy_on_rename
+// which deletes it too
+func topicsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	c, ok := meta.(*Client)
+	if !ok {
+		return nil
+	}
+	// HasChange is also true for a state with the alias of the cluster, which planClusterId does not replace
+	clusterChanged := !d.NewValueKnown("cluster_id")
+	if d.HasChange("cluster_id") {
+		o, n := d.GetChange("cluster_id")
+		oldId, _ := c.resolveClusterId("cluster_id", o.(string))
+		newId, _ := c.resolveClusterId("cluster_id", n.(string))
+		clusterChanged = clusterChanged || oldId != newId
+	}
+	if !d.HasChange("name") && !clusterChanged {
+		return nil
+	}
+
+	old, _ := d.GetChange("name")
+	oldProtection, _ := d.GetChange("deletion_protection")
+	if protected, reason := c.isTopicProtected(old.(string), oldProtection.(bool)); protected {
+		tflog.SubsystemWarn(ctx, logKafka, "Refusing to replace the protected topic", map[string]interface{}{"topic": old, "reason": reason})
+		return fmt.Errorf("topic %s cannot be replaced because %s", old, reason)
+	}
+	return nil
+}
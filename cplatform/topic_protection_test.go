@@ -0,0 +1,139 @@
+package cplatform
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestTopicsCustomizeDiff_protected(t *testing.T) {
+	c := &Client{kafka: fakeSaramaClient{}, defaultKafkaClusterId: fakeClusterId, protectTopics: []*regexp.Regexp{regexp.MustCompile("^prod-")}}
+
+	for _, tc := range []struct {
+		name       string
+		protection bool
+		config     map[string]interface{}
+		err        bool
+	}{
+		// the replacement of a protected topic
+		{"payments", true, map[string]interface{}{"name": "payments-v2"}, true},
+		{"prod-payments", false, map[string]interface{}{"name": "prod-payments-v2"}, true},
+		// the rename by copy deletes the old topic too
+		{"payments", true, map[string]interface{}{"name": "payments-v2", "copy_on_rename": true}, true},
+		{"prod-payments", false, map[string]interface{}{"name": "payments-v2", "copy_on_rename": true}, true},
+		{"payments", false, map[string]interface{}{"name": "payments-v2", "copy_on_rename": true}, false},
+		{"payments", false, map[string]interface{}{"name": "payments-v2"}, false},
+		// the topic is not deleted
+		{"payments", true, map[string]interface{}{"partitions": 6}, false},
+	} {
+		state := &terraform.InstanceState{
+			ID: tc.name,
+			Attributes: map[string]string{
+				"id":                  tc.name,
+				"name":                tc.name,
+				"partitions":          "3",
+				"cluster_id":          fakeClusterId,
+				"deletion_protection": "false",
+			},
+		}
+		config := map[string]interface{}{"name": tc.name, "partitions": 3, "cluster_id": fakeClusterId}
+		if tc.protection {
+			state.Attributes["deletion_protection"] = "true"
+			config["deletion_protection"] = true
+		}
+		for k, v := range tc.config {
+			config[k] = v
+		}
+
+		_, err := topics().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), c)
+		if tc.err != (err != nil) {
+			t.Errorf("%s %v: got %v, expected an error: %t", tc.name, tc.config, err, tc.err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "cannot be replaced") {
+			t.Errorf("%s %v: unexpected error %v", tc.name, tc.config, err)
+		}
+	}
+}
+
+func TestTopicsDelete_protected(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+	k.groups["payments-service"] = map[string]map[int32]int64{"payments": {0: 10}}
+	c := k.client(t, "")
+
+	for _, tc := range []struct {
+		attributes map[string]interface{}
+		protect    string
+		err        string
+	}{
+		{map[string]interface{}{"deletion_protection": true}, "", "deletion_protection is true"},
+		{nil, "^pay", `it matches "^pay"`},
+		{map[string]interface{}{"deletion_check_consumers": true}, "", "payments-service have committed offsets"},
+	} {
+		c.protectTopics = nil
+		if tc.protect != "" {
+			c.protectTopics = []*regexp.Regexp{regexp.MustCompile(tc.protect)}
+		}
+		attributes := testTopicAttributes(3, 2, "")
+		for k, v := range tc.attributes {
+			attributes[k] = v
+		}
+		d := schema.TestResourceDataRaw(t, topics().Schema, attributes)
+		d.SetId(buildId(fakeClusterId, "payments"))
+
+		diags := topicsDelete(context.Background(), d, c)
+		if !diags.HasError() || !strings.Contains(diags[0].Detail, tc.err) {
+			t.Errorf("%v: got %v, expected %q", tc.attributes, diags, tc.err)
+		}
+		if _, ok := k.topic("payments"); !ok {
+			t.Fatalf("%v: the protected topic was deleted", tc.attributes)
+		}
+	}
+}
+
+func TestTopicsRename_protected(t *testing.T) {
+	k := newFakeKafka(t, newFakeConfluent(t))
+	k.createTopic("payments", 3, 2, nil)
+	k.groups["payments-service"] = map[string]map[int32]int64{"payments": {0: 10}}
+	c := k.client(t, "")
+
+	state := testTopicAttributes(3, 2, "")
+	state["copy_on_rename"] = true
+	config := testTopicAttributes(3, 2, "")
+	config["name"] = "payments-v2"
+	config["copy_on_rename"] = true
+
+	// protect_topics_matching changed after the plan
+	ctx := context.Background()
+	d := schema.TestResourceDataRaw(t, topics().Schema, state)
+	d.SetId(buildId(fakeClusterId, "payments"))
+	diff, err := topics().Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.protectTopics = []*regexp.Regexp{regexp.MustCompile("^payments$")}
+	_, diags := topics().Apply(ctx, d.State(), diff, c)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "protected against deletion") {
+		t.Errorf("expected the rename of the protected topic to fail, got %v", diags)
+	}
+	c.protectTopics = nil
+
+	// the consumer groups keep their offsets on the old topic
+	state["deletion_check_consumers"] = true
+	config["deletion_check_consumers"] = true
+	_, diags = applyTopic(t, c, state, config)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "still has consumers") {
+		t.Errorf("expected the rename of the consumed topic to fail, got %v", diags)
+	}
+
+	if _, ok := k.topic("payments-v2"); ok {
+		t.Errorf("the new topic was created")
+	}
+	if _, ok := k.topic("payments"); !ok {
+		t.Errorf("the old topic was deleted")
+	}
+}
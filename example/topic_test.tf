@@ -1,5 +1,9 @@
+data "confluent_cluster" "main" {
+  provider = confluent-kafka.confluent
+}
+
 resource "cluster_role_binding" "example_role_binding" {
-  cluster_id = "cluster-1"
+  cluster_id = data.confluent_cluster.main.cluster_id
   role = "UserAdmin"
   principal = "User:user-test"
   cluster_type = "Kafka"
@@ -7,7 +11,7 @@ resource "cluster_role_binding" "example_role_binding" {
 }
 
 resource "cluster_role_binding" "example_role_binding_operator" {
-  cluster_id = "cluster-1"
+  cluster_id = data.confluent_cluster.main.cluster_id
   role = "Operator"
   principal = "User:user-test"
   cluster_type = "Kafka"
@@ -20,7 +24,7 @@ resource "kafka_topic_rbac" "example_topic_rbac" {
   resource_type = "Topic"
   pattern_type = "PREFIXED"
   name = "test-"
-  cluster_id = "cluster-1"
+  cluster_id = data.confluent_cluster.main.cluster_id
   provider = confluent-kafka.confluent
 }
 
@@ -30,12 +34,12 @@ resource "kafka_topic_rbac" "example_cn_topic_rbac" {
   resource_type = "Topic"
   pattern_type = "LITERAL"
   name = "test-terraform-confluent-provider"
-  cluster_id = "cluster-1"
+  cluster_id = data.confluent_cluster.main.cluster_id
   provider = confluent-kafka.confluent
 }
 
 resource "kafka_topic" "example_topic" {
-  cluster_id = "cluster-1"
+  cluster_id = data.confluent_cluster.main.cluster_id
   name = "test-terraform-confluent-provider"
   replication_factor = 3
   partitions = 5
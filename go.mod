@@ -3,6 +3,11 @@ module github.com/OneMount/terraform-provider-confluent
 go 1.16
 
 require (
-	github.com/hashicorp/terraform-plugin-sdk/v2 v2.7.0
 	github.com/OneMount/gonfluent v0.1.1
+	github.com/Shopify/sarama v1.29.1
+	github.com/hashicorp/go-cty v1.4.1-0.20200414143053-d3edf31b6320
+	github.com/hashicorp/terraform-plugin-log v0.7.0
+	github.com/hashicorp/terraform-plugin-sdk/v2 v2.7.0
+	github.com/twmb/franz-go v1.0.0
+	github.com/twmb/franz-go/pkg/kmsg v0.0.0-20210901051457-3c197a133ddd
 )